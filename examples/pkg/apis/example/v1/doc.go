@@ -15,4 +15,5 @@ limitations under the License.
 */
 
 // +k8s:deepcopy-gen=package,register
+// +groupName=example.dev
 package v1
@@ -64,3 +64,25 @@ type ClusterTestTypeList struct {
 
 	Items []ClusterTestType `json:"items"`
 }
+
+// +genclient
+// +genclient:readonly
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReadOnlyTestType is a top-level type which can only be read. Only the
+// Get, List and Watch client methods are created for it.
+type ReadOnlyTestType struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ReadOnlyTestTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ReadOnlyTestType `json:"items"`
+}
@@ -22,13 +22,18 @@ limitations under the License.
 package clusterclient
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	kcp "github.com/kcp-dev/apimachinery/pkg/client"
 	"github.com/kcp-dev/code-generator/examples/pkg/generated/clientset/versioned"
 	"github.com/kcp-dev/logicalcluster"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/code-generator/examples/pkg/clusterclient/internal"
 
 	examplev1client "github.com/kcp-dev/code-generator/examples/pkg/clusterclient/typed/example/v1"
 	examplev1 "github.com/kcp-dev/code-generator/examples/pkg/generated/clientset/versioned/typed/example/v1"
@@ -38,49 +43,171 @@ import (
 // It uses a custom round tripper that wraps the given client's
 // endpoint. The clientset returned from NewForConfig is kcp
 // cluster-aware.
-func NewForConfig(config *rest.Config) (*ClusterClient, error) {
+func NewForConfig(config *rest.Config, options ...ClusterClientOption) (*ClusterClient, error) {
 	client, err := rest.HTTPClientFor(config)
 	if err != nil {
 		return nil, fmt.Errorf("error creating HTTP client: %w", err)
 	}
+	return NewForConfigAndClient(config, client, options...)
+}
 
-	clusterRoundTripper := kcp.NewClusterRoundTripper(client.Transport)
-	client.Transport = clusterRoundTripper
-
-	delegate, err := versioned.NewForConfigAndClient(config, client)
+// NewForConfigAndClient creates a new ClusterClient for the given config and HTTP client.
+// The requests are sent through a copy of the HTTP client, whose transport is wrapped with
+// the custom round tripper unless it already is, so that the same HTTP client, and thus its
+// connections, can be shared by several clientsets. The given HTTP client is not modified.
+func NewForConfigAndClient(config *rest.Config, client *http.Client, options ...ClusterClientOption) (*ClusterClient, error) {
+	delegate, err := versioned.NewForConfigAndClient(config, clusterHTTPClient(client))
 	if err != nil {
 		return nil, fmt.Errorf("error creating delegate clientset: %w", err)
 	}
 
-	return &ClusterClient{
+	return newClusterClient(delegate, options), nil
+}
+
+// NewForConfigOrDie creates a new ClusterClient for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(config *rest.Config, options ...ClusterClientOption) *ClusterClient {
+	c, err := NewForConfig(config, options...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// New creates a new ClusterClient for the given RESTClient. If it is a *rest.RESTClient,
+// its requests are sent through a copy of its HTTP client wrapped like in
+// NewForConfigAndClient. Otherwise, it is expected to send the requests to the logical
+// cluster of their context itself.
+func New(c rest.Interface, options ...ClusterClientOption) *ClusterClient {
+	if restClient, ok := c.(*rest.RESTClient); ok && restClient != nil {
+		httpClient := http.DefaultClient
+		if restClient.Client != nil {
+			httpClient = restClient.Client
+		}
+		wrapped := *restClient
+		wrapped.Client = clusterHTTPClient(httpClient)
+		c = &wrapped
+	}
+	return newClusterClient(versioned.New(c), options)
+}
+
+// newClusterClient creates a ClusterClient wrapping the delegate, with the given options.
+func newClusterClient(delegate versioned.Interface, options []ClusterClientOption) *ClusterClient {
+	c := &ClusterClient{
 		delegate: delegate,
-	}, nil
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
 }
 
+// ClusterClientOption configures a ClusterClient.
+type ClusterClientOption func(*ClusterClient)
+
+// WithClusterMismatchPolicy sets what the clients do with the requests whose context has a
+// logical cluster different from the one the clients are scoped to.
+func WithClusterMismatchPolicy(policy ClusterMismatchPolicy) ClusterClientOption {
+	return func(c *ClusterClient) {
+		c.clusterMismatchPolicy = policy
+	}
+}
+
+// ClusterMismatchFunc is called for a request whose context has the logical cluster ctxCluster,
+// different from the logical cluster of the client. It returns the context to send the request
+// with, or the error to fail it with. It is taken by the typed clients created with
+// NewWithClusterMismatch and NewClusterWithClusterMismatch.
+type ClusterMismatchFunc = internal.ClusterMismatchFunc
+
+// ClusterMismatchPolicy is what the clients do with the requests whose context has a
+// logical cluster different from the one the clients are scoped to.
+type ClusterMismatchPolicy int
+
+const (
+	// ClusterMismatchError fails the requests. It is the default.
+	ClusterMismatchError ClusterMismatchPolicy = iota
+	// ClusterMismatchPreferContext sends the requests to the logical cluster of their context.
+	ClusterMismatchPreferContext
+	// ClusterMismatchPreferClient sends the requests to the logical cluster of the client.
+	ClusterMismatchPreferClient
+	// ClusterMismatchLogAndContinue logs the mismatch, and sends the requests to the logical
+	// cluster of their context.
+	ClusterMismatchLogAndContinue
+)
+
+// onMismatch returns the context to send a request with, whose context has the logical
+// cluster ctxCluster instead of the cluster of the client, according to the policy.
+func (p ClusterMismatchPolicy) onMismatch(ctx context.Context, ctxCluster, cluster logicalcluster.Name) (context.Context, error) {
+	switch p {
+	case ClusterMismatchPreferContext:
+		return ctx, nil
+	case ClusterMismatchPreferClient:
+		return kcp.WithCluster(ctx, cluster), nil
+	case ClusterMismatchLogAndContinue:
+		klog.Warningf("cluster mismatch: context=%q, client=%q, sending the request to the cluster of the context", ctxCluster, cluster)
+		return ctx, nil
+	default:
+		return ctx, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, cluster)
+	}
+}
+
+// clusterHTTPClient returns a copy of the HTTP client whose transport is wrapped with
+// kcp.ClusterRoundTripper, unless it already is.
+func clusterHTTPClient(client *http.Client) *http.Client {
+	result := *client
+	switch result.Transport.(type) {
+	case *kcp.ClusterRoundTripper:
+	case nil:
+		result.Transport = kcp.NewClusterRoundTripper(http.DefaultTransport)
+	default:
+		result.Transport = kcp.NewClusterRoundTripper(result.Transport)
+	}
+	return &result
+}
+
+// ClusterInterface scopes a clientset to a particular logical cluster, and offers
+// the clients of the group versions which can scope down to a logical cluster.
+// It is implemented by ClusterClient and by the fake ClusterClientset.
+type ClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) versioned.Interface
+	ExampleV1() examplev1client.ExampleV1ClusterInterface
+}
+
+var _ ClusterInterface = &ClusterClient{}
+
 // ClusterClient wraps the underlying interface.
 type ClusterClient struct {
-	delegate versioned.Interface
+	delegate              versioned.Interface
+	clusterMismatchPolicy ClusterMismatchPolicy
 }
 
 // Cluster returns a wrapped interface scoped to a particular cluster.
 func (c *ClusterClient) Cluster(cluster logicalcluster.Name) versioned.Interface {
 	return &wrappedInterface{
-		cluster:  cluster,
-		delegate: c.delegate,
+		cluster:               cluster,
+		delegate:              c.delegate,
+		clusterMismatchPolicy: c.clusterMismatchPolicy,
 	}
 }
 
 type wrappedInterface struct {
-	cluster  logicalcluster.Name
-	delegate versioned.Interface
+	cluster               logicalcluster.Name
+	delegate              versioned.Interface
+	clusterMismatchPolicy ClusterMismatchPolicy
 }
 
-// Discovery retrieves the DiscoveryClient.
+// Discovery retrieves the DiscoveryClient, which sends its requests to the logical cluster.
 func (w *wrappedInterface) Discovery() discovery.DiscoveryInterface {
-	return w.delegate.Discovery()
+	return newDiscovery(w.cluster, w.delegate.Discovery(), w.clusterMismatchPolicy.onMismatch)
+}
+
+// ExampleV1 retrieves a client listing and watching ExampleV1 resources
+// across all logical clusters.
+func (c *ClusterClient) ExampleV1() examplev1client.ExampleV1ClusterInterface {
+	return examplev1client.NewClusterWithClusterMismatch(c.delegate.ExampleV1(), c.clusterMismatchPolicy.onMismatch)
 }
 
 // ExampleV1 retrieves the ExampleV1Client.
 func (w *wrappedInterface) ExampleV1() examplev1.ExampleV1Interface {
-	return examplev1client.New(w.cluster, w.delegate.ExampleV1())
+	return examplev1client.NewWithClusterMismatch(w.cluster, w.delegate.ExampleV1(), w.clusterMismatchPolicy.onMismatch)
 }
@@ -0,0 +1,385 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+
+	kcp "github.com/kcp-dev/apimachinery/pkg/client"
+	"github.com/kcp-dev/logicalcluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	examplev1client "github.com/kcp-dev/code-generator/examples/pkg/clusterclient/typed/example/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// server records the requests sent by the clients, and answers them with empty
+// objects and lists of the example API.
+type server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []string
+}
+
+// kinds are the kinds of the resources of the example API.
+var kinds = map[string]string{
+	"testtypes":         "TestType",
+	"clustertesttypes":  "ClusterTestType",
+	"readonlytesttypes": "ReadOnlyTestType",
+}
+
+func newServer() *server {
+	s := &server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		request := r.URL.Path
+		if r.URL.RawQuery != "" {
+			request += "?" + r.URL.RawQuery
+		}
+		s.requests = append(s.requests, request)
+		s.lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("watch") == "true" {
+			// the watch ends with the response.
+			return
+		}
+		switch base := path.Base(r.URL.Path); {
+		case base == "api":
+			fmt.Fprint(w, `{"kind":"APIVersions","versions":["v1"]}`)
+		case base == "apis":
+			fmt.Fprint(w, `{"kind":"APIGroupList","groups":[]}`)
+		case kinds[base] != "":
+			fmt.Fprintf(w, `{"kind":"%sList","apiVersion":"example.dev/v1","items":[]}`, kinds[base])
+		case kinds[path.Base(path.Dir(r.URL.Path))] != "":
+			fmt.Fprintf(w, `{"kind":"%s","apiVersion":"example.dev/v1","metadata":{"name":%q}}`, kinds[path.Base(path.Dir(r.URL.Path))], base)
+		default:
+			fmt.Fprintf(w, `{"kind":"APIResourceList","groupVersion":"v1","resources":[]}`)
+		}
+	}))
+	return s
+}
+
+// Requests returns the paths and queries of the requests received since the last call.
+func (s *server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+var _ = Describe("Test cluster-wide requests", func() {
+	var (
+		ctx = context.Background()
+
+		srv    *server
+		client *ClusterClient
+	)
+	BeforeEach(func() {
+		srv = newServer()
+		var err error
+		client, err = NewForConfig(&rest.Config{Host: srv.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("should list the namespaced resources of all logical clusters", func() {
+		_, err := client.ExampleV1().TestTypes().List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/*/apis/example.dev/v1/testtypes"}))
+	})
+
+	It("should watch the namespaced resources of all logical clusters", func() {
+		w, err := client.ExampleV1().TestTypes().Watch(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		w.Stop()
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/*/apis/example.dev/v1/testtypes?watch=true"}))
+	})
+
+	It("should list and watch the cluster-scoped resources of all logical clusters", func() {
+		_, err := client.ExampleV1().ClusterTestTypes().List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		w, err := client.ExampleV1().ClusterTestTypes().Watch(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		w.Stop()
+		Expect(srv.Requests()).To(Equal([]string{
+			"/clusters/*/apis/example.dev/v1/clustertesttypes",
+			"/clusters/*/apis/example.dev/v1/clustertesttypes?watch=true",
+		}))
+	})
+
+	It("should list and watch the read-only resources of all logical clusters", func() {
+		_, err := client.ExampleV1().ReadOnlyTestTypes().List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		w, err := client.ExampleV1().ReadOnlyTestTypes().Watch(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		w.Stop()
+		Expect(srv.Requests()).To(Equal([]string{
+			"/clusters/*/apis/example.dev/v1/readonlytesttypes",
+			"/clusters/*/apis/example.dev/v1/readonlytesttypes?watch=true",
+		}))
+	})
+
+	It("should fail the requests whose context has a logical cluster", func() {
+		ctx := kcp.WithCluster(ctx, logicalcluster.New("root:org"))
+		_, err := client.ExampleV1().TestTypes().List(ctx, metav1.ListOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(srv.Requests()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Test scoping to a logical cluster", func() {
+	var (
+		ctx = context.Background()
+		org = logicalcluster.New("root:org")
+
+		srv    *server
+		client *ClusterClient
+	)
+	BeforeEach(func() {
+		srv = newServer()
+		var err error
+		client, err = NewForConfig(&rest.Config{Host: srv.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("should scope the clientset", func() {
+		_, err := client.Cluster(org).ExampleV1().TestTypes("default").Get(ctx, "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/namespaces/default/testtypes/name"}))
+	})
+
+	It("should scope the group version", func() {
+		_, err := client.ExampleV1().Cluster(org).TestTypes("default").Get(ctx, "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/namespaces/default/testtypes/name"}))
+	})
+
+	It("should scope the namespaced types", func() {
+		_, err := client.ExampleV1().TestTypes().Cluster(org).Namespace("default").Get(ctx, "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.ExampleV1().ReadOnlyTestTypes().Cluster(org).Namespace("default").List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{
+			"/clusters/root:org/apis/example.dev/v1/namespaces/default/testtypes/name",
+			"/clusters/root:org/apis/example.dev/v1/namespaces/default/readonlytesttypes",
+		}))
+	})
+
+	It("should scope the cluster-scoped types", func() {
+		_, err := client.ExampleV1().ClusterTestTypes().Cluster(org).Get(ctx, "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/clustertesttypes/name"}))
+	})
+
+	It("should accept the requests whose context has the same logical cluster", func() {
+		ctx := kcp.WithCluster(ctx, org)
+		_, err := client.ExampleV1().TestTypes().Cluster(org).Namespace("default").Get(ctx, "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/namespaces/default/testtypes/name"}))
+	})
+})
+
+var _ = Describe("Test RESTClient", func() {
+	var (
+		ctx   = context.Background()
+		org   = logicalcluster.New("root:org")
+		other = logicalcluster.New("root:other")
+
+		srv    *server
+		client *ClusterClient
+	)
+	BeforeEach(func() {
+		srv = newServer()
+		var err error
+		client, err = NewForConfig(&rest.Config{Host: srv.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	get := func(ctx context.Context) error {
+		return client.Cluster(org).ExampleV1().RESTClient().Get().
+			Namespace("default").Resource("testtypes").Name("name").SubResource("status").
+			Do(ctx).Error()
+	}
+
+	It("should send the requests to the logical cluster", func() {
+		Expect(get(ctx)).To(Succeed())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/namespaces/default/testtypes/name/status"}))
+	})
+
+	It("should accept the requests whose context has the same logical cluster", func() {
+		Expect(get(kcp.WithCluster(ctx, org))).To(Succeed())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/namespaces/default/testtypes/name/status"}))
+	})
+
+	It("should fail the requests whose context has a different logical cluster", func() {
+		err := get(kcp.WithCluster(ctx, other))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cluster mismatch"))
+		Expect(srv.Requests()).To(BeEmpty())
+	})
+
+	It("should not change the RESTClient of the delegate", func() {
+		Expect(client.Cluster(org).ExampleV1().RESTClient()).NotTo(BeIdenticalTo(client.delegate.ExampleV1().RESTClient()))
+		Expect(client.delegate.ExampleV1().RESTClient().Get().AbsPath("/apis").Do(kcp.WithCluster(ctx, other)).Error()).To(Succeed())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:other/apis"}))
+	})
+})
+
+var _ = Describe("Test constructors", func() {
+	var (
+		ctx = context.Background()
+		org = logicalcluster.New("root:org")
+
+		srv    *server
+		config *rest.Config
+	)
+	BeforeEach(func() {
+		srv = newServer()
+		config = &rest.Config{Host: srv.URL}
+	})
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	get := func(client *ClusterClient) {
+		_, err := client.Cluster(org).ExampleV1().ClusterTestTypes().Get(ctx, "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/apis/example.dev/v1/clustertesttypes/name"}))
+	}
+
+	It("should not modify an HTTP client shared by several clientsets", func() {
+		httpClient := srv.Client()
+		transport := httpClient.Transport
+
+		first, err := NewForConfigAndClient(config, httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := NewForConfigAndClient(config, httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpClient.Transport).To(BeIdenticalTo(transport))
+
+		get(first)
+		get(second)
+	})
+
+	It("should not wrap an HTTP client already wrapped", func() {
+		httpClient := &http.Client{Transport: kcp.NewClusterRoundTripper(srv.Client().Transport)}
+		client, err := NewForConfigAndClient(config, httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		get(client)
+	})
+
+	It("should not wrap the RESTClient of a ClusterClient again", func() {
+		client, err := NewForConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		get(New(client.delegate.ExampleV1().RESTClient()))
+	})
+
+	It("should create a ClusterClient for a config without transport", func() {
+		get(NewForConfigOrDie(config))
+	})
+})
+
+var _ = Describe("Test cluster mismatch policies", func() {
+	var (
+		ctx   = context.Background()
+		org   = logicalcluster.New("root:org")
+		other = logicalcluster.New("root:other")
+
+		srv *server
+	)
+	BeforeEach(func() {
+		srv = newServer()
+	})
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	DescribeTable("should handle the requests whose context has a different logical cluster",
+		func(policy ClusterMismatchPolicy, cluster string) {
+			client, err := NewForConfig(&rest.Config{Host: srv.URL}, WithClusterMismatchPolicy(policy))
+			Expect(err).NotTo(HaveOccurred())
+			ctx := kcp.WithCluster(ctx, other)
+
+			_, typedErr := client.Cluster(org).ExampleV1().TestTypes("default").Get(ctx, "name", metav1.GetOptions{})
+			restErr := client.Cluster(org).ExampleV1().RESTClient().Get().
+				Namespace("default").Resource("testtypes").Name("name").SubResource("status").
+				Do(ctx).Error()
+			_, scopedErr := client.ExampleV1().TestTypes().Cluster(org).Namespace("default").Get(ctx, "name", metav1.GetOptions{})
+			if cluster == "" {
+				Expect(typedErr).To(MatchError(ContainSubstring("cluster mismatch")))
+				Expect(restErr).To(MatchError(ContainSubstring("cluster mismatch")))
+				Expect(scopedErr).To(MatchError(ContainSubstring("cluster mismatch")))
+				Expect(srv.Requests()).To(BeEmpty())
+				return
+			}
+			Expect(typedErr).NotTo(HaveOccurred())
+			Expect(restErr).NotTo(HaveOccurred())
+			Expect(scopedErr).NotTo(HaveOccurred())
+			Expect(srv.Requests()).To(Equal([]string{
+				"/clusters/" + cluster + "/apis/example.dev/v1/namespaces/default/testtypes/name",
+				"/clusters/" + cluster + "/apis/example.dev/v1/namespaces/default/testtypes/name/status",
+				"/clusters/" + cluster + "/apis/example.dev/v1/namespaces/default/testtypes/name",
+			}))
+		},
+		Entry("by failing them by default", ClusterMismatchError, ""),
+		Entry("by sending them to the logical cluster of the context", ClusterMismatchPreferContext, "root:other"),
+		Entry("by sending them to the logical cluster of the client", ClusterMismatchPreferClient, "root:org"),
+		Entry("by logging and sending them to the logical cluster of the context", ClusterMismatchLogAndContinue, "root:other"),
+	)
+
+	It("should pass a ClusterMismatchFunc to the typed clients", func() {
+		client, err := NewForConfig(&rest.Config{Host: srv.URL})
+		Expect(err).NotTo(HaveOccurred())
+		var onMismatch ClusterMismatchFunc = func(ctx context.Context, ctxCluster, cluster logicalcluster.Name) (context.Context, error) {
+			return kcp.WithCluster(ctx, ctxCluster), nil
+		}
+		typed := examplev1client.NewWithClusterMismatch(org, client.delegate.ExampleV1(), onMismatch)
+
+		_, err = typed.TestTypes("default").Get(kcp.WithCluster(ctx, other), "name", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{
+			"/clusters/root:other/apis/example.dev/v1/namespaces/default/testtypes/name",
+		}))
+	})
+})
+
+func TestClusterClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cluster client suite")
+}
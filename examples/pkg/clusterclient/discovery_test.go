@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclient
+
+import (
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+	"k8s.io/client-go/rest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test discovery", func() {
+	var (
+		org   = logicalcluster.New("root:org")
+		other = logicalcluster.New("root:other")
+
+		srv    *server
+		client *ClusterClient
+	)
+	BeforeEach(func() {
+		srv = newServer()
+		var err error
+		client, err = NewForConfig(&rest.Config{Host: srv.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	// inCluster checks that every request was sent to the given logical cluster.
+	inCluster := func(cluster logicalcluster.Name, requests []string) {
+		Expect(requests).NotTo(BeEmpty())
+		for _, request := range requests {
+			Expect(strings.HasPrefix(request, cluster.Path()+"/")).To(BeTrue(), "request %s", request)
+		}
+	}
+
+	It("should send the discovery requests to the logical cluster", func() {
+		_, err := client.Cluster(org).Discovery().ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(Equal([]string{"/clusters/root:org/api", "/clusters/root:org/apis"}))
+	})
+
+	It("should cache the discovery per logical cluster", func() {
+		cached := NewCachedDiscovery(client)
+
+		_, err := cached.Cluster(org).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		inCluster(org, srv.Requests())
+
+		_, err = cached.Cluster(org).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(BeEmpty())
+
+		_, err = cached.Cluster(other).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		inCluster(other, srv.Requests())
+	})
+
+	It("should fetch the discovery again once invalidated", func() {
+		cached := NewCachedDiscovery(client)
+		_, err := cached.Cluster(org).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cached.Cluster(other).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		srv.Requests()
+
+		cached.Invalidate(org)
+		_, err = cached.Cluster(org).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		inCluster(org, srv.Requests())
+
+		_, err = cached.Cluster(other).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(BeEmpty())
+	})
+
+	It("should drop the cache of a forgotten logical cluster", func() {
+		cached := NewCachedDiscovery(client)
+		forgotten := cached.Cluster(org)
+		_, err := forgotten.ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		srv.Requests()
+
+		cached.Forget(org)
+		Expect(cached.Cluster(org)).NotTo(BeIdenticalTo(forgotten))
+		_, err = cached.Cluster(org).ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		inCluster(org, srv.Requests())
+
+		// the cached discovery clients already returned keep their cache.
+		_, err = forgotten.ServerGroups()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Requests()).To(BeEmpty())
+	})
+})
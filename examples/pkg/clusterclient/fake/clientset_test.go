@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	examplev1 "github.com/kcp-dev/code-generator/examples/pkg/apis/example/v1"
+)
+
+var _ = Describe("Test fake cluster clientset", func() {
+	var (
+		ctx   = context.Background()
+		org   = logicalcluster.New("root:org")
+		other = logicalcluster.New("root:other")
+
+		client *ClusterClientset
+	)
+	BeforeEach(func() {
+		client = NewSimpleClientset(
+			&examplev1.TestType{ObjectMeta: metav1.ObjectMeta{Name: "org", Namespace: "default", ClusterName: org.String()}},
+			&examplev1.TestType{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", ClusterName: other.String()}},
+		)
+	})
+
+	names := func(cluster logicalcluster.Name) []string {
+		list, err := client.Cluster(cluster).ExampleV1().TestTypes("default").List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var result []string
+		for _, item := range list.Items {
+			result = append(result, item.Name)
+		}
+		return result
+	}
+
+	It("should add the objects to the tracker of their logical cluster", func() {
+		Expect(names(org)).To(ConsistOf("org"))
+		Expect(names(other)).To(ConsistOf("other"))
+	})
+
+	It("should not write to the other logical clusters", func() {
+		_, err := client.Cluster(org).ExampleV1().TestTypes("default").Create(ctx, &examplev1.TestType{ObjectMeta: metav1.ObjectMeta{Name: "created"}}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Cluster(other).ExampleV1().TestTypes("default").Delete(ctx, "org", metav1.DeleteOptions{})).NotTo(Succeed())
+
+		Expect(names(org)).To(ConsistOf("org", "created"))
+		Expect(names(other)).To(ConsistOf("other"))
+	})
+
+	It("should start from an empty tracker for a new logical cluster", func() {
+		Expect(names(logicalcluster.New("root:new"))).To(BeEmpty())
+	})
+
+	It("should list the objects of every logical cluster from the wildcard cluster", func() {
+		list, err := client.Cluster(logicalcluster.Wildcard).ExampleV1().TestTypes("default").List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		clusters := map[string]logicalcluster.Name{}
+		for i := range list.Items {
+			clusters[list.Items[i].Name] = logicalcluster.From(&list.Items[i])
+		}
+		Expect(clusters).To(Equal(map[string]logicalcluster.Name{"org": org, "other": other}))
+	})
+
+	It("should watch the objects of every logical cluster from the wildcard cluster", func() {
+		w, err := client.Cluster(logicalcluster.Wildcard).ExampleV1().TestTypes("default").Watch(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		defer w.Stop()
+
+		created := logicalcluster.New("root:created")
+		_, err = client.Cluster(created).ExampleV1().TestTypes("default").Create(ctx, &examplev1.TestType{ObjectMeta: metav1.ObjectMeta{Name: "created"}}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var event watch.Event
+		Eventually(w.ResultChan()).Should(Receive(&event))
+		Expect(event.Type).To(Equal(watch.Added))
+		Expect(logicalcluster.From(event.Object.(*examplev1.TestType))).To(Equal(created))
+	})
+
+	It("should scope the clients of the group versions down to the fake clientset of a logical cluster", func() {
+		testType, err := client.ExampleV1().Cluster(org).TestTypes("default").Get(ctx, "org", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testType.Name).To(Equal("org"))
+		_, err = client.ExampleV1().TestTypes().Cluster(other).Namespace("default").Get(ctx, "org", metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+
+		list, err := client.ExampleV1().TestTypes().List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Items).To(HaveLen(2))
+	})
+
+	It("should record the actions per logical cluster", func() {
+		names(org)
+		Expect(client.Actions(org)).To(HaveLen(1))
+		Expect(client.Actions(org)[0].GetVerb()).To(Equal("list"))
+		Expect(client.Actions(other)).To(BeEmpty())
+
+		_, err := client.Tracker(other).Get(examplev1.SchemeGroupVersion.WithResource("testtypes"), "default", "other")
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+func TestFakeClientset(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fake cluster clientset suite")
+}
@@ -33,46 +33,109 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/code-generator/examples/pkg/clusterclient/internal"
 )
 
 // WrappedExampleV1 wraps the client interface with a
 // logical cluster.
 type WrappedExampleV1 struct {
-	cluster  logicalcluster.Name
-	delegate examplev1.ExampleV1Interface
+	cluster    logicalcluster.Name
+	delegate   examplev1.ExampleV1Interface
+	onMismatch internal.ClusterMismatchFunc
 }
 
 // New creates a WrappedExampleV1 with the given logical cluster and client interface.
 func New(cluster logicalcluster.Name, delegate examplev1.ExampleV1Interface) *WrappedExampleV1 {
-	return &WrappedExampleV1{cluster: cluster, delegate: delegate}
+	return NewWithClusterMismatch(cluster, delegate, nil)
+}
+
+// NewWithClusterMismatch creates a WrappedExampleV1 with the given logical cluster and client interface,
+// which calls onMismatch for the requests whose context has a different logical cluster. They
+// fail if it is nil.
+func NewWithClusterMismatch(cluster logicalcluster.Name, delegate examplev1.ExampleV1Interface, onMismatch internal.ClusterMismatchFunc) *WrappedExampleV1 {
+	return &WrappedExampleV1{cluster: cluster, delegate: delegate, onMismatch: onMismatch}
 }
 
-// RESTClient returns the underlying RESTClient.
+// RESTClient returns a copy of the underlying RESTClient, whose requests are sent to the
+// logical cluster. Requests whose context has a different logical cluster are handled like
+// by the typed clients.
 func (w *WrappedExampleV1) RESTClient() rest.Interface {
-	return w.delegate.RESTClient()
+	restClient, ok := w.delegate.RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		// such as the client of a fake clientset, which sends no requests.
+		return w.delegate.RESTClient()
+	}
+	return internal.WithCluster(w.cluster, restClient, w.onMismatch)
+}
+
+// ClusterExampleV1 lists and watches the resources of the group version
+// across all logical clusters.
+type ClusterExampleV1 struct {
+	clientFor func(cluster logicalcluster.Name) examplev1.ExampleV1Interface
+}
+
+// NewCluster creates a ClusterExampleV1 with the given client interface.
+func NewCluster(delegate examplev1.ExampleV1Interface) *ClusterExampleV1 {
+	return NewClusterWithClusterMismatch(delegate, nil)
 }
 
-// WrappedExampleV1 contains the wrapped logical cluster and interface.
+// NewClusterWithClusterMismatch creates a ClusterExampleV1 with the given client interface, whose
+// clients call onMismatch like the ones created by NewWithClusterMismatch.
+func NewClusterWithClusterMismatch(delegate examplev1.ExampleV1Interface, onMismatch internal.ClusterMismatchFunc) *ClusterExampleV1 {
+	return NewClusterForClients(func(cluster logicalcluster.Name) examplev1.ExampleV1Interface {
+		return NewWithClusterMismatch(cluster, delegate, onMismatch)
+	})
+}
+
+// NewClusterForClients creates a ClusterExampleV1 whose client of a logical cluster is
+// returned by clientFor, such as the client of the fake clientset of the logical cluster.
+func NewClusterForClients(clientFor func(cluster logicalcluster.Name) examplev1.ExampleV1Interface) *ClusterExampleV1 {
+	return &ClusterExampleV1{clientFor: clientFor}
+}
+
+// ExampleV1ClusterInterface can scope down to the client of the group version
+// for a single logical cluster, or to the clients of its resources.
+type ExampleV1ClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) examplev1.ExampleV1Interface
+	ClusterTestTypesClusterGetter
+	ReadOnlyTestTypesClusterGetter
+	TestTypesClusterGetter
+}
+
+var _ ExampleV1ClusterInterface = &ClusterExampleV1{}
+
+// Cluster returns the client of the group version scoped to the given logical cluster.
+func (c *ClusterExampleV1) Cluster(cluster logicalcluster.Name) examplev1.ExampleV1Interface {
+	return c.clientFor(cluster)
+}
+
+// ClusterTestTypes returns a client for the ClusterTestTypes of the logical cluster.
 func (w *WrappedExampleV1) ClusterTestTypes() examplev1.ClusterTestTypeInterface {
 	return &wrappedClusterTestType{
-		cluster:  w.cluster,
-		delegate: w.delegate.ClusterTestTypes(),
+		cluster:    w.cluster,
+		delegate:   w.delegate.ClusterTestTypes(),
+		onMismatch: w.onMismatch,
 	}
 }
 
 type wrappedClusterTestType struct {
-	cluster  logicalcluster.Name
-	delegate examplev1.ClusterTestTypeInterface
+	cluster    logicalcluster.Name
+	delegate   examplev1.ClusterTestTypeInterface
+	onMismatch internal.ClusterMismatchFunc
 }
 
 // checkCluster retrieves the logical cluster name from the given context and checks
-// if it is the same as the one passed while creating a wrappedClusterTestType. It errors when
-// there is a mismatch.
+// if it is the same as the one passed while creating a wrappedClusterTestType. On a mismatch,
+// it returns the result of onMismatch, or an error if it is nil.
 func (w *wrappedClusterTestType) checkCluster(ctx context.Context) (context.Context, error) {
 	ctxCluster, ok := kcp.ClusterFromContext(ctx)
 	if !ok {
 		return kcp.WithCluster(ctx, w.cluster), nil
 	} else if ctxCluster != w.cluster {
+		if w.onMismatch != nil {
+			return w.onMismatch(ctx, ctxCluster, w.cluster)
+		}
 		return ctx, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, w.cluster)
 	}
 	return ctx, nil
@@ -87,6 +150,51 @@ func (w *wrappedClusterTestType) Create(ctx context.Context, clusterTestType *ex
 	return w.delegate.Create(ctx, clusterTestType, opts)
 }
 
+// Delete implements ClusterTestTypeInterface.
+func (w *wrappedClusterTestType) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	ctx, err := w.checkCluster(ctx)
+	if err != nil {
+		return err
+	}
+	return w.delegate.Delete(ctx, name, opts)
+}
+
+// DeleteCollection implements ClusterTestTypeInterface.
+func (w *wrappedClusterTestType) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	ctx, err := w.checkCluster(ctx)
+	if err != nil {
+		return err
+	}
+	return w.delegate.DeleteCollection(ctx, opts, listOpts)
+}
+
+// Get implements ClusterTestTypeInterface.
+func (w *wrappedClusterTestType) Get(ctx context.Context, name string, opts metav1.GetOptions) (*exampleapiv1.ClusterTestType, error) {
+	ctx, err := w.checkCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.delegate.Get(ctx, name, opts)
+}
+
+// List implements ClusterTestTypeInterface.
+func (w *wrappedClusterTestType) List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ClusterTestTypeList, error) {
+	ctx, err := w.checkCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.delegate.List(ctx, opts)
+}
+
+// Patch implements ClusterTestTypeInterface.
+func (w *wrappedClusterTestType) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*exampleapiv1.ClusterTestType, error) {
+	ctx, err := w.checkCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.delegate.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
 // Update implements ClusterTestTypeInterface.
 func (w *wrappedClusterTestType) Update(ctx context.Context, clusterTestType *exampleapiv1.ClusterTestType, opts metav1.UpdateOptions) (*exampleapiv1.ClusterTestType, error) {
 	ctx, err := w.checkCluster(ctx)
@@ -96,7 +204,7 @@ func (w *wrappedClusterTestType) Update(ctx context.Context, clusterTestType *ex
 	return w.delegate.Update(ctx, clusterTestType, opts)
 }
 
-// UpdateStatus implements ClusterTestTypeInterface. It was generated because the type contains a Status member.
+// UpdateStatus implements ClusterTestTypeInterface.
 func (w *wrappedClusterTestType) UpdateStatus(ctx context.Context, clusterTestType *exampleapiv1.ClusterTestType, opts metav1.UpdateOptions) (*exampleapiv1.ClusterTestType, error) {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
@@ -105,26 +213,88 @@ func (w *wrappedClusterTestType) UpdateStatus(ctx context.Context, clusterTestTy
 	return w.delegate.UpdateStatus(ctx, clusterTestType, opts)
 }
 
-// Update implements ClusterTestTypeInterface.
-func (w *wrappedClusterTestType) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+// Watch implements ClusterTestTypeInterface.
+func (w *wrappedClusterTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return w.delegate.Delete(ctx, name, opts)
+	return w.delegate.Watch(ctx, opts)
 }
 
-// DeleteCollection implements ClusterTestTypeInterface.
-func (w *wrappedClusterTestType) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listopts metav1.ListOptions) error {
-	ctx, err := w.checkCluster(ctx)
-	if err != nil {
-		return err
+// ClusterTestTypesClusterGetter has a method to return a ClusterTestTypeClusterInterface.
+type ClusterTestTypesClusterGetter interface {
+	ClusterTestTypes() ClusterTestTypeClusterInterface
+}
+
+// ClusterTestTypeClusterInterface can scope down to a single logical cluster.
+// It also lists and watches ClusterTestTypes across all logical clusters, and
+// the logical cluster of an item is found with logicalcluster.From.
+type ClusterTestTypeClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) examplev1.ClusterTestTypeInterface
+	List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ClusterTestTypeList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// ClusterTestTypes returns a client for ClusterTestTypes, which can scope down to a logical cluster.
+func (c *ClusterExampleV1) ClusterTestTypes() ClusterTestTypeClusterInterface {
+	return &clusterClusterTestType{delegate: c}
+}
+
+// clusterClusterTestType scopes ClusterTestTypes down to a logical cluster, and offers the methods
+// of wrappedClusterTestType which can be used with the wildcard logical cluster.
+type clusterClusterTestType struct {
+	delegate *ClusterExampleV1
+}
+
+// Cluster implements ClusterTestTypeClusterInterface.
+func (c *clusterClusterTestType) Cluster(cluster logicalcluster.Name) examplev1.ClusterTestTypeInterface {
+	return c.delegate.Cluster(cluster).ClusterTestTypes()
+}
+
+// List implements ClusterTestTypeClusterInterface.
+func (c *clusterClusterTestType) List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ClusterTestTypeList, error) {
+	return c.delegate.Cluster(logicalcluster.Wildcard).ClusterTestTypes().List(ctx, opts)
+}
+
+// Watch implements ClusterTestTypeClusterInterface.
+func (c *clusterClusterTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.delegate.Cluster(logicalcluster.Wildcard).ClusterTestTypes().Watch(ctx, opts)
+}
+
+// ReadOnlyTestTypes returns a client for the ReadOnlyTestTypes of the logical cluster in the namespace.
+func (w *WrappedExampleV1) ReadOnlyTestTypes(namespace string) examplev1.ReadOnlyTestTypeInterface {
+	return &wrappedReadOnlyTestType{
+		cluster:    w.cluster,
+		delegate:   w.delegate.ReadOnlyTestTypes(namespace),
+		onMismatch: w.onMismatch,
 	}
-	return w.delegate.DeleteCollection(ctx, opts, listopts)
 }
 
-// Get implements ClusterTestTypeInterface.
-func (w *wrappedClusterTestType) Get(ctx context.Context, name string, opts metav1.GetOptions) (*exampleapiv1.ClusterTestType, error) {
+type wrappedReadOnlyTestType struct {
+	cluster    logicalcluster.Name
+	delegate   examplev1.ReadOnlyTestTypeInterface
+	onMismatch internal.ClusterMismatchFunc
+}
+
+// checkCluster retrieves the logical cluster name from the given context and checks
+// if it is the same as the one passed while creating a wrappedReadOnlyTestType. On a mismatch,
+// it returns the result of onMismatch, or an error if it is nil.
+func (w *wrappedReadOnlyTestType) checkCluster(ctx context.Context) (context.Context, error) {
+	ctxCluster, ok := kcp.ClusterFromContext(ctx)
+	if !ok {
+		return kcp.WithCluster(ctx, w.cluster), nil
+	} else if ctxCluster != w.cluster {
+		if w.onMismatch != nil {
+			return w.onMismatch(ctx, ctxCluster, w.cluster)
+		}
+		return ctx, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, w.cluster)
+	}
+	return ctx, nil
+}
+
+// Get implements ReadOnlyTestTypeInterface.
+func (w *wrappedReadOnlyTestType) Get(ctx context.Context, name string, opts metav1.GetOptions) (*exampleapiv1.ReadOnlyTestType, error) {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
 		return nil, err
@@ -132,8 +302,8 @@ func (w *wrappedClusterTestType) Get(ctx context.Context, name string, opts meta
 	return w.delegate.Get(ctx, name, opts)
 }
 
-// List implements ClusterTestTypeInterface.
-func (w *wrappedClusterTestType) List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ClusterTestTypeList, error) {
+// List implements ReadOnlyTestTypeInterface.
+func (w *wrappedReadOnlyTestType) List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ReadOnlyTestTypeList, error) {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
 		return nil, err
@@ -141,8 +311,8 @@ func (w *wrappedClusterTestType) List(ctx context.Context, opts metav1.ListOptio
 	return w.delegate.List(ctx, opts)
 }
 
-// Watch implements ClusterTestTypeInterface.
-func (w *wrappedClusterTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+// Watch implements ReadOnlyTestTypeInterface.
+func (w *wrappedReadOnlyTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
 		return nil, err
@@ -150,36 +320,87 @@ func (w *wrappedClusterTestType) Watch(ctx context.Context, opts metav1.ListOpti
 	return w.delegate.Watch(ctx, opts)
 }
 
-// Patch implements ClusterTestTypeInterface.
-func (w *wrappedClusterTestType) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *exampleapiv1.ClusterTestType, err error) {
-	ctx, err = w.checkCluster(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return w.delegate.Patch(ctx, name, pt, data, opts, subresources...)
+// ReadOnlyTestTypesClusterGetter has a method to return a ReadOnlyTestTypeClusterInterface.
+type ReadOnlyTestTypesClusterGetter interface {
+	ReadOnlyTestTypes() ReadOnlyTestTypeClusterInterface
+}
+
+// ReadOnlyTestTypeClusterInterface can scope down to a single logical cluster and namespace.
+// It also lists and watches ReadOnlyTestTypes across all logical clusters and namespaces, and
+// the logical cluster of an item is found with logicalcluster.From.
+type ReadOnlyTestTypeClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) ReadOnlyTestTypesNamespacer
+	List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ReadOnlyTestTypeList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// ReadOnlyTestTypes returns a client for ReadOnlyTestTypes, which can scope down to a logical cluster.
+func (c *ClusterExampleV1) ReadOnlyTestTypes() ReadOnlyTestTypeClusterInterface {
+	return &clusterReadOnlyTestType{delegate: c}
 }
 
-// WrappedExampleV1 contains the wrapped logical cluster and interface.
+// clusterReadOnlyTestType scopes ReadOnlyTestTypes down to a logical cluster, and offers the methods
+// of wrappedReadOnlyTestType which can be used with the wildcard logical cluster.
+type clusterReadOnlyTestType struct {
+	delegate *ClusterExampleV1
+}
+
+// Cluster implements ReadOnlyTestTypeClusterInterface.
+func (c *clusterReadOnlyTestType) Cluster(cluster logicalcluster.Name) ReadOnlyTestTypesNamespacer {
+	return &readOnlyTestTypesNamespacer{cluster: cluster, delegate: c.delegate}
+}
+
+// List implements ReadOnlyTestTypeClusterInterface.
+func (c *clusterReadOnlyTestType) List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.ReadOnlyTestTypeList, error) {
+	return c.delegate.Cluster(logicalcluster.Wildcard).ReadOnlyTestTypes(metav1.NamespaceAll).List(ctx, opts)
+}
+
+// Watch implements ReadOnlyTestTypeClusterInterface.
+func (c *clusterReadOnlyTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.delegate.Cluster(logicalcluster.Wildcard).ReadOnlyTestTypes(metav1.NamespaceAll).Watch(ctx, opts)
+}
+
+// ReadOnlyTestTypesNamespacer can scope down to a single namespace of a logical cluster.
+type ReadOnlyTestTypesNamespacer interface {
+	Namespace(namespace string) examplev1.ReadOnlyTestTypeInterface
+}
+
+type readOnlyTestTypesNamespacer struct {
+	cluster  logicalcluster.Name
+	delegate *ClusterExampleV1
+}
+
+// Namespace implements ReadOnlyTestTypesNamespacer.
+func (n *readOnlyTestTypesNamespacer) Namespace(namespace string) examplev1.ReadOnlyTestTypeInterface {
+	return n.delegate.Cluster(n.cluster).ReadOnlyTestTypes(namespace)
+}
+
+// TestTypes returns a client for the TestTypes of the logical cluster in the namespace.
 func (w *WrappedExampleV1) TestTypes(namespace string) examplev1.TestTypeInterface {
 	return &wrappedTestType{
-		cluster:  w.cluster,
-		delegate: w.delegate.TestTypes(namespace),
+		cluster:    w.cluster,
+		delegate:   w.delegate.TestTypes(namespace),
+		onMismatch: w.onMismatch,
 	}
 }
 
 type wrappedTestType struct {
-	cluster  logicalcluster.Name
-	delegate examplev1.TestTypeInterface
+	cluster    logicalcluster.Name
+	delegate   examplev1.TestTypeInterface
+	onMismatch internal.ClusterMismatchFunc
 }
 
 // checkCluster retrieves the logical cluster name from the given context and checks
-// if it is the same as the one passed while creating a wrappedTestType. It errors when
-// there is a mismatch.
+// if it is the same as the one passed while creating a wrappedTestType. On a mismatch,
+// it returns the result of onMismatch, or an error if it is nil.
 func (w *wrappedTestType) checkCluster(ctx context.Context) (context.Context, error) {
 	ctxCluster, ok := kcp.ClusterFromContext(ctx)
 	if !ok {
 		return kcp.WithCluster(ctx, w.cluster), nil
 	} else if ctxCluster != w.cluster {
+		if w.onMismatch != nil {
+			return w.onMismatch(ctx, ctxCluster, w.cluster)
+		}
 		return ctx, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, w.cluster)
 	}
 	return ctx, nil
@@ -194,16 +415,7 @@ func (w *wrappedTestType) Create(ctx context.Context, testType *exampleapiv1.Tes
 	return w.delegate.Create(ctx, testType, opts)
 }
 
-// Update implements TestTypeInterface.
-func (w *wrappedTestType) Update(ctx context.Context, testType *exampleapiv1.TestType, opts metav1.UpdateOptions) (*exampleapiv1.TestType, error) {
-	ctx, err := w.checkCluster(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return w.delegate.Update(ctx, testType, opts)
-}
-
-// Update implements TestTypeInterface.
+// Delete implements TestTypeInterface.
 func (w *wrappedTestType) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
@@ -213,12 +425,12 @@ func (w *wrappedTestType) Delete(ctx context.Context, name string, opts metav1.D
 }
 
 // DeleteCollection implements TestTypeInterface.
-func (w *wrappedTestType) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listopts metav1.ListOptions) error {
+func (w *wrappedTestType) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
 		return err
 	}
-	return w.delegate.DeleteCollection(ctx, opts, listopts)
+	return w.delegate.DeleteCollection(ctx, opts, listOpts)
 }
 
 // Get implements TestTypeInterface.
@@ -239,20 +451,84 @@ func (w *wrappedTestType) List(ctx context.Context, opts metav1.ListOptions) (*e
 	return w.delegate.List(ctx, opts)
 }
 
-// Watch implements TestTypeInterface.
-func (w *wrappedTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+// Patch implements TestTypeInterface.
+func (w *wrappedTestType) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*exampleapiv1.TestType, error) {
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return w.delegate.Watch(ctx, opts)
+	return w.delegate.Patch(ctx, name, pt, data, opts, subresources...)
 }
 
-// Patch implements TestTypeInterface.
-func (w *wrappedTestType) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *exampleapiv1.TestType, err error) {
-	ctx, err = w.checkCluster(ctx)
+// Update implements TestTypeInterface.
+func (w *wrappedTestType) Update(ctx context.Context, testType *exampleapiv1.TestType, opts metav1.UpdateOptions) (*exampleapiv1.TestType, error) {
+	ctx, err := w.checkCluster(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return w.delegate.Patch(ctx, name, pt, data, opts, subresources...)
+	return w.delegate.Update(ctx, testType, opts)
+}
+
+// Watch implements TestTypeInterface.
+func (w *wrappedTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	ctx, err := w.checkCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.delegate.Watch(ctx, opts)
+}
+
+// TestTypesClusterGetter has a method to return a TestTypeClusterInterface.
+type TestTypesClusterGetter interface {
+	TestTypes() TestTypeClusterInterface
+}
+
+// TestTypeClusterInterface can scope down to a single logical cluster and namespace.
+// It also lists and watches TestTypes across all logical clusters and namespaces, and
+// the logical cluster of an item is found with logicalcluster.From.
+type TestTypeClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) TestTypesNamespacer
+	List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.TestTypeList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// TestTypes returns a client for TestTypes, which can scope down to a logical cluster.
+func (c *ClusterExampleV1) TestTypes() TestTypeClusterInterface {
+	return &clusterTestType{delegate: c}
+}
+
+// clusterTestType scopes TestTypes down to a logical cluster, and offers the methods
+// of wrappedTestType which can be used with the wildcard logical cluster.
+type clusterTestType struct {
+	delegate *ClusterExampleV1
+}
+
+// Cluster implements TestTypeClusterInterface.
+func (c *clusterTestType) Cluster(cluster logicalcluster.Name) TestTypesNamespacer {
+	return &testTypesNamespacer{cluster: cluster, delegate: c.delegate}
+}
+
+// List implements TestTypeClusterInterface.
+func (c *clusterTestType) List(ctx context.Context, opts metav1.ListOptions) (*exampleapiv1.TestTypeList, error) {
+	return c.delegate.Cluster(logicalcluster.Wildcard).TestTypes(metav1.NamespaceAll).List(ctx, opts)
+}
+
+// Watch implements TestTypeClusterInterface.
+func (c *clusterTestType) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.delegate.Cluster(logicalcluster.Wildcard).TestTypes(metav1.NamespaceAll).Watch(ctx, opts)
+}
+
+// TestTypesNamespacer can scope down to a single namespace of a logical cluster.
+type TestTypesNamespacer interface {
+	Namespace(namespace string) examplev1.TestTypeInterface
+}
+
+type testTypesNamespacer struct {
+	cluster  logicalcluster.Name
+	delegate *ClusterExampleV1
+}
+
+// Namespace implements TestTypesNamespacer.
+func (n *testTypesNamespacer) Namespace(namespace string) examplev1.TestTypeInterface {
+	return n.delegate.Cluster(n.cluster).TestTypes(namespace)
 }
@@ -29,10 +29,11 @@ import (
 type ExampleV1Interface interface {
 	RESTClient() rest.Interface
 	ClusterTestTypesGetter
+	ReadOnlyTestTypesGetter
 	TestTypesGetter
 }
 
-// ExampleV1Client is used to interact with features provided by the example group.
+// ExampleV1Client is used to interact with features provided by the example.dev group.
 type ExampleV1Client struct {
 	restClient rest.Interface
 }
@@ -41,6 +42,10 @@ func (c *ExampleV1Client) ClusterTestTypes() ClusterTestTypeInterface {
 	return newClusterTestTypes(c)
 }
 
+func (c *ExampleV1Client) ReadOnlyTestTypes(namespace string) ReadOnlyTestTypeInterface {
+	return newReadOnlyTestTypes(c, namespace)
+}
+
 func (c *ExampleV1Client) TestTypes(namespace string) TestTypeInterface {
 	return newTestTypes(c, namespace)
 }
@@ -32,6 +32,10 @@ func (c *FakeExampleV1) ClusterTestTypes() v1.ClusterTestTypeInterface {
 	return &FakeClusterTestTypes{c}
 }
 
+func (c *FakeExampleV1) ReadOnlyTestTypes(namespace string) v1.ReadOnlyTestTypeInterface {
+	return &FakeReadOnlyTestTypes{c, namespace}
+}
+
 func (c *FakeExampleV1) TestTypes(namespace string) v1.TestTypeInterface {
 	return &FakeTestTypes{c, namespace}
 }
@@ -0,0 +1,80 @@
+/*
+Copyright The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen-v0.24.0. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	examplev1 "github.com/kcp-dev/code-generator/examples/pkg/apis/example/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeReadOnlyTestTypes implements ReadOnlyTestTypeInterface
+type FakeReadOnlyTestTypes struct {
+	Fake *FakeExampleV1
+	ns   string
+}
+
+var readonlytesttypesResource = schema.GroupVersionResource{Group: "example.dev", Version: "v1", Resource: "readonlytesttypes"}
+
+var readonlytesttypesKind = schema.GroupVersionKind{Group: "example.dev", Version: "v1", Kind: "ReadOnlyTestType"}
+
+// Get takes name of the readOnlyTestType, and returns the corresponding readOnlyTestType object, and an error if there is any.
+func (c *FakeReadOnlyTestTypes) Get(ctx context.Context, name string, options v1.GetOptions) (result *examplev1.ReadOnlyTestType, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(readonlytesttypesResource, c.ns, name), &examplev1.ReadOnlyTestType{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*examplev1.ReadOnlyTestType), err
+}
+
+// List takes label and field selectors, and returns the list of ReadOnlyTestTypes that match those selectors.
+func (c *FakeReadOnlyTestTypes) List(ctx context.Context, opts v1.ListOptions) (result *examplev1.ReadOnlyTestTypeList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(readonlytesttypesResource, readonlytesttypesKind, c.ns, opts), &examplev1.ReadOnlyTestTypeList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &examplev1.ReadOnlyTestTypeList{ListMeta: obj.(*examplev1.ReadOnlyTestTypeList).ListMeta}
+	for _, item := range obj.(*examplev1.ReadOnlyTestTypeList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested readOnlyTestTypes.
+func (c *FakeReadOnlyTestTypes) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(readonlytesttypesResource, c.ns, opts))
+
+}
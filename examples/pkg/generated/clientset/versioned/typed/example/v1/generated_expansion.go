@@ -20,4 +20,6 @@ package v1
 
 type ClusterTestTypeExpansion interface{}
 
+type ReadOnlyTestTypeExpansion interface{}
+
 type TestTypeExpansion interface{}
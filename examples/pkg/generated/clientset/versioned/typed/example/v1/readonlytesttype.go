@@ -0,0 +1,103 @@
+/*
+Copyright The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen-v0.24.0. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	examplev1 "github.com/kcp-dev/code-generator/examples/pkg/apis/example/v1"
+	scheme "github.com/kcp-dev/code-generator/examples/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ReadOnlyTestTypesGetter has a method to return a ReadOnlyTestTypeInterface.
+// A group's client should implement this interface.
+type ReadOnlyTestTypesGetter interface {
+	ReadOnlyTestTypes(namespace string) ReadOnlyTestTypeInterface
+}
+
+// ReadOnlyTestTypeInterface has methods to work with ReadOnlyTestType resources.
+type ReadOnlyTestTypeInterface interface {
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*examplev1.ReadOnlyTestType, error)
+	List(ctx context.Context, opts v1.ListOptions) (*examplev1.ReadOnlyTestTypeList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	ReadOnlyTestTypeExpansion
+}
+
+// readOnlyTestTypes implements ReadOnlyTestTypeInterface
+type readOnlyTestTypes struct {
+	client rest.Interface
+	ns     string
+}
+
+// newReadOnlyTestTypes returns a ReadOnlyTestTypes
+func newReadOnlyTestTypes(c *ExampleV1Client, namespace string) *readOnlyTestTypes {
+	return &readOnlyTestTypes{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the readOnlyTestType, and returns the corresponding readOnlyTestType object, and an error if there is any.
+func (c *readOnlyTestTypes) Get(ctx context.Context, name string, options v1.GetOptions) (result *examplev1.ReadOnlyTestType, err error) {
+	result = &examplev1.ReadOnlyTestType{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("readonlytesttypes").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ReadOnlyTestTypes that match those selectors.
+func (c *readOnlyTestTypes) List(ctx context.Context, opts v1.ListOptions) (result *examplev1.ReadOnlyTestTypeList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &examplev1.ReadOnlyTestTypeList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("readonlytesttypes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested readOnlyTestTypes.
+func (c *readOnlyTestTypes) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("readonlytesttypes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
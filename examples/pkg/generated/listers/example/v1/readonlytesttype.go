@@ -0,0 +1,99 @@
+/*
+Copyright The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen-v0.24.0. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/kcp-dev/code-generator/examples/pkg/apis/example/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ReadOnlyTestTypeLister helps list ReadOnlyTestTypes.
+// All objects returned here must be treated as read-only.
+type ReadOnlyTestTypeLister interface {
+	// List lists all ReadOnlyTestTypes in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ReadOnlyTestType, err error)
+	// ReadOnlyTestTypes returns an object that can list and get ReadOnlyTestTypes.
+	ReadOnlyTestTypes(namespace string) ReadOnlyTestTypeNamespaceLister
+	ReadOnlyTestTypeListerExpansion
+}
+
+// readOnlyTestTypeLister implements the ReadOnlyTestTypeLister interface.
+type readOnlyTestTypeLister struct {
+	indexer cache.Indexer
+}
+
+// NewReadOnlyTestTypeLister returns a new ReadOnlyTestTypeLister.
+func NewReadOnlyTestTypeLister(indexer cache.Indexer) ReadOnlyTestTypeLister {
+	return &readOnlyTestTypeLister{indexer: indexer}
+}
+
+// List lists all ReadOnlyTestTypes in the indexer.
+func (s *readOnlyTestTypeLister) List(selector labels.Selector) (ret []*v1.ReadOnlyTestType, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ReadOnlyTestType))
+	})
+	return ret, err
+}
+
+// ReadOnlyTestTypes returns an object that can list and get ReadOnlyTestTypes.
+func (s *readOnlyTestTypeLister) ReadOnlyTestTypes(namespace string) ReadOnlyTestTypeNamespaceLister {
+	return readOnlyTestTypeNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ReadOnlyTestTypeNamespaceLister helps list and get ReadOnlyTestTypes.
+// All objects returned here must be treated as read-only.
+type ReadOnlyTestTypeNamespaceLister interface {
+	// List lists all ReadOnlyTestTypes in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ReadOnlyTestType, err error)
+	// Get retrieves the ReadOnlyTestType from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ReadOnlyTestType, error)
+	ReadOnlyTestTypeNamespaceListerExpansion
+}
+
+// readOnlyTestTypeNamespaceLister implements the ReadOnlyTestTypeNamespaceLister
+// interface.
+type readOnlyTestTypeNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ReadOnlyTestTypes in the indexer for a given namespace.
+func (s readOnlyTestTypeNamespaceLister) List(selector labels.Selector) (ret []*v1.ReadOnlyTestType, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ReadOnlyTestType))
+	})
+	return ret, err
+}
+
+// Get retrieves the ReadOnlyTestType from the indexer for a given namespace and name.
+func (s readOnlyTestTypeNamespaceLister) Get(name string) (*v1.ReadOnlyTestType, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("readonlytesttype"), name)
+	}
+	return obj.(*v1.ReadOnlyTestType), nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	examplev1 "github.com/kcp-dev/code-generator/examples/pkg/apis/example/v1"
+	"github.com/kcp-dev/code-generator/examples/pkg/clusterclient/fake"
+)
+
+var _ = Describe("Test shared informer factory", func() {
+	It("should list and watch through the fake cluster clientset", func() {
+		ctx := context.Background()
+		org, other := logicalcluster.New("root:org"), logicalcluster.New("root:other")
+		client := fake.NewSimpleClientset(&examplev1.TestType{ObjectMeta: metav1.ObjectMeta{Name: "listed", Namespace: "default", ClusterName: org.String()}})
+
+		factory := NewSharedInformerFactory(client, 0)
+		informer := factory.ExampleV1().TestTypes()
+		informer.Informer()
+
+		stop := make(chan struct{})
+		defer close(stop)
+		factory.Start(stop)
+		for _, synced := range factory.WaitForCacheSync(stop) {
+			Expect(synced).To(BeTrue())
+		}
+
+		listed, err := informer.Lister().Cluster(org).TestTypes("default").Get("listed")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed.Name).To(Equal("listed"))
+
+		// the objects created once the informer started are watched, including
+		// in the logical clusters which were not used yet.
+		_, err = client.Cluster(other).ExampleV1().TestTypes("default").Create(ctx, &examplev1.TestType{ObjectMeta: metav1.ObjectMeta{Name: "watched"}}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() error {
+			_, err := informer.Lister().Cluster(other).TestTypes("default").Get("watched")
+			return err
+		}).Should(Succeed())
+		_, err = informer.Lister().Cluster(org).TestTypes("default").Get("watched")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func TestInformers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shared informer factory suite")
+}
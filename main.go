@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -28,15 +29,100 @@ import (
 	"github.com/kcp-dev/code-generator/pkg/flag"
 	"github.com/kcp-dev/code-generator/pkg/generators"
 	"github.com/kcp-dev/code-generator/pkg/generators/clientgen"
+	"github.com/kcp-dev/code-generator/pkg/generators/informergen"
+	"github.com/kcp-dev/code-generator/pkg/generators/listergen"
+	"github.com/kcp-dev/code-generator/pkg/util"
 )
 
 var (
 	allGenerators = map[string]generators.Generator{
-		"client": clientgen.Generator{},
+		"client":   clientgen.Generator{},
+		"lister":   listergen.Generator{},
+		"informer": informergen.Generator{},
 	}
 )
 
+// enabledGenerators returns the generators named in the argument, which is
+// expected to be of the form "client,lister,informer".
+func enabledGenerators(arg string) []generators.Generator {
+	enabled := []generators.Generator{}
+	for _, gName := range strings.Split(arg, ",") {
+		if gen, ok := allGenerators[gName]; ok {
+			enabled = append(enabled, gen)
+		}
+	}
+	return enabled
+}
+
+// run runs the given generators with the flags, writing their output through the
+// output rule.
+func run(enabledGenerators []generators.Generator, f flag.Flags, output genall.OutputRule) error {
+	if len(enabledGenerators) == 0 {
+		return fmt.Errorf("no generator ran.")
+	}
+
+	for _, generator := range enabledGenerators {
+		reg, err := generator.RegisterMarker()
+		if err != nil {
+			return fmt.Errorf("error registering markers in generator %s", generator.GetName())
+		}
+
+		ctx := &genall.GenerationContext{Collector: &markers.Collector{Registry: reg}, OutputRule: output}
+		if err := generator.Run(ctx, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prune stages the deletion of the previously generated files found in the given
+// directories which the run did not produce, so that they are deleted when the
+// generated files are committed. They are only listed with --prune-dry-run, and
+// reported as stale with --verify.
+func prune(out io.Writer, f flag.Flags, tracked *util.TrackedOutput, verify *util.VerifyOutput, staged *util.StagedOutput, dirs []string) error {
+	stale, err := tracked.Stale(dirs...)
+	if err != nil {
+		return fmt.Errorf("error finding stale generated files: %w", err)
+	}
+	for _, path := range stale {
+		switch {
+		case f.Verify:
+			if err := verify.Removed(path); err != nil {
+				return err
+			}
+		case f.PruneDryRun:
+			fmt.Fprintf(out, "would delete %s\n", path)
+		default:
+			fmt.Fprintf(out, "deleting %s\n", path)
+		}
+	}
+	if !f.Verify && !f.PruneDryRun {
+		staged.Remove(stale, dirs...)
+	}
+	return nil
+}
+
 func main() {
+	os.Exit(execute(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// execute runs the command with the given arguments and returns its exit code,
+// which is non-zero when it fails, such as when --verify finds stale files.
+func execute(args []string, stdout, stderr io.Writer) int {
+	cmd := newCommand()
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintf(stderr, "error running all markers: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newCommand returns the command generating the cluster-aware wrappers.
+func newCommand() *cobra.Command {
 	f := &flag.Flags{}
 	cmd := &cobra.Command{
 		Use:   "code-gen",
@@ -51,53 +137,105 @@ func main() {
 						  --output-dir examples/pkg 
 						  --group-versions example:v1
 		
-		# To generate listers and informers (Yet to be implemented):
+		# To generate client wrappers and cluster-aware listers:
+		code-gen "client,lister" --clientset-name clusterclient --go-header-file examples/header.txt 
+						  --clientset-api-path=github.com/kcp-dev/code-generator/examples/pkg/generated/clientset/versioned 
+						  --listers-api-path=github.com/kcp-dev/code-generator/examples/pkg/generated/listers 
+						  --input-dir github.com/kcp-dev/code-generator/examples 
+						  --output-dir examples/pkg 
+						  --group-versions example:v1
+
+		# To generate client wrappers, cluster-aware listers and informers:
 		code-gen "client,lister,informer" --clientset-name clusterclient --go-header-file examples/header.txt 
 						  --clientset-api-path=github.com/kcp-dev/code-generator/examples/pkg/generated/clientset/versioned 
+						  --listers-api-path=github.com/kcp-dev/code-generator/examples/pkg/generated/listers 
 						  --input-dir github.com/kcp-dev/code-generator/examples 
 						  --output-dir examples/pkg 
 						  --group-versions example:v1
+
+		# To generate the clientsets described in a config file, overriding its header file:
+		code-gen --config codegen.yaml --go-header-file examples/header.txt
+
+		# To verify that the generated clientsets are up to date, without writing them:
+		code-gen --config codegen.yaml --verify
 		`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
-				return fmt.Errorf("no arguments provided to the command. Accepted values are clients, informers and listers.")
+			// The generated files are staged until every generator succeeded, so
+			// that a failure leaves the output tree untouched. When verifying, the
+			// output is only compared with the files on disk, and the command fails
+			// if any of them is stale.
+			staged := &util.StagedOutput{Force: f.Force}
+			defer staged.Discard()
+			var output genall.OutputRule = staged
+			verify := &util.VerifyOutput{Out: cmd.OutOrStdout()}
+			if f.Verify {
+				output = verify
 			}
-
-			// This argument is expected to be of the form
-			// "client,lister,informer". Based on the arguments,
-			// enable the suitable generator.
-			cmdArgs := strings.Split(args[0], ",")
-			enabledGenerators := []generators.Generator{}
-			for _, gName := range cmdArgs {
-				if gen, ok := allGenerators[gName]; ok {
-					enabledGenerators = append(enabledGenerators, gen)
+			// The files written are tracked to prune the generated files which are
+			// no longer produced, once every generator succeeded. Their deletion is
+			// staged along with the generated files.
+			tracked := &util.TrackedOutput{OutputRule: output}
+			var dirs []string
+			finish := func() error {
+				if err := prune(cmd.OutOrStdout(), *f, tracked, verify, staged, dirs); err != nil {
+					return err
+				}
+				if err := staged.Commit(); err != nil {
+					return err
 				}
+				return verify.Err()
 			}
 
-			if len(enabledGenerators) == 0 {
-				return fmt.Errorf("no generator ran.")
+			if f.ConfigFile == "" {
+				if len(args) == 0 {
+					return fmt.Errorf("no arguments provided to the command. Accepted values are clients, informers and listers.")
+				}
+				cmd.SilenceUsage = true
+				gens := enabledGenerators(args[0])
+				if err := run(gens, *f, tracked); err != nil {
+					return err
+				}
+				for _, gen := range gens {
+					dirs = append(dirs, gen.OutputDir(*f))
+				}
+				return finish()
 			}
 
-			for _, generator := range enabledGenerators {
-				reg, err := generator.RegisterMarker()
-				if err != nil {
-					return fmt.Errorf("error registering markers in generator %s", generator.GetName())
+			c, err := flag.LoadConfig(f.ConfigFile)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			for _, cs := range c.Clientsets {
+				// The generators given as argument override the ones of the file.
+				var gens []generators.Generator
+				if len(args) > 0 {
+					gens = enabledGenerators(args[0])
+				} else {
+					if len(cs.Generators) == 0 {
+						return cs.Errorf("generators", "no generators are set")
+					}
+					for _, gName := range cs.Generators {
+						gen, ok := allGenerators[gName]
+						if !ok {
+							return cs.Errorf("generators", "unknown generator %q. Accepted values are client, lister and informer", gName)
+						}
+						gens = append(gens, gen)
+					}
 				}
 
-				ctx := &genall.GenerationContext{Collector: &markers.Collector{Registry: reg}}
-				if err := generator.Run(ctx, *f); err != nil {
-					return err
+				flags := cs.Merge(*f, cmd.Flags())
+				if err := run(gens, flags, tracked); err != nil {
+					return cs.Errorf("", "%w", err)
+				}
+				for _, gen := range gens {
+					dirs = append(dirs, gen.OutputDir(flags))
 				}
 			}
-
-			return nil
+			return finish()
 		},
 	}
 
 	f.AddTo(cmd.Flags())
-
-	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "error running all markers: %v\n", err)
-		os.Exit(1)
-	}
+	return cmd
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/kcp-dev/code-generator/pkg/util"
+)
+
+func TestCommand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Command Suite")
+}
+
+// copyTree copies the files of the slash-separated paths, relative to the root of the
+// module, to the given directory.
+func copyTree(dst string, paths ...string) {
+	for _, path := range paths {
+		Expect(filepath.WalkDir(filepath.FromSlash(path), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			target := filepath.Join(dst, path)
+			if d.IsDir() {
+				return os.MkdirAll(target, 0755)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, content, 0644)
+		})).To(Succeed())
+	}
+}
+
+var _ = Describe("Test verify", func() {
+	var (
+		dir, config    string
+		stdout, stderr *bytes.Buffer
+	)
+	// The command runs against a copy of the examples, so that the checked-in
+	// tree is never modified, even when a spec fails.
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "command")
+		Expect(err).NotTo(HaveOccurred())
+		copyTree(dir, "go.mod", "go.sum", "examples", "hack/boilerplate")
+		config = filepath.Join(dir, "examples", "codegen.yaml")
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should succeed when the examples are up to date", func() {
+		Expect(execute([]string{"--config", config, "--verify"}, stdout, stderr)).To(Equal(0))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+
+	Context("with a stale generated file", func() {
+		var stale string
+		BeforeEach(func() {
+			stale = filepath.Join(dir, "examples", "pkg", "listers", "stale")
+			Expect(os.MkdirAll(filepath.Join(stale, "v1"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(stale, "v1", "stalev1.go"), []byte(util.GeneratedMarker+"\n\npackage v1\n"), 0644)).To(Succeed())
+		})
+
+		It("should print its diff and fail", func() {
+			Expect(execute([]string{"--config", config, "--verify"}, stdout, stderr)).To(Equal(1))
+			Expect(stdout.String()).To(ContainSubstring("+++ /dev/null\n"))
+			Expect(stdout.String()).To(ContainSubstring("-package v1\n"))
+			Expect(stderr.String()).To(ContainSubstring("generated files are out of date"))
+			Expect(filepath.Join(stale, "v1", "stalev1.go")).To(BeAnExistingFile())
+		})
+
+		It("should delete it along with the directories it leaves empty", func() {
+			Expect(execute([]string{"--config", config}, stdout, stderr)).To(Equal(0))
+			Expect(stdout.String()).To(Equal("deleting " + filepath.Join(stale, "v1", "stalev1.go") + "\n"))
+			Expect(stale).NotTo(BeADirectory())
+			Expect(filepath.Join(dir, "examples", "pkg", "listers")).To(BeADirectory())
+		})
+	})
+})
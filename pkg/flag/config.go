@@ -0,0 +1,341 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the content of the file given with --config. It lists the clientsets to
+// generate, each of which is generated as if the generators were invoked with the
+// flags it describes. For example:
+//
+//	clientsets:
+//	- name: clusterclient
+//	  generators: [client, lister, informer]
+//	  inputDir: ./pkg/apis
+//	  outputDir: ./pkg
+//	  clientsetAPIPath: github.com/kcp-dev/code-generator/examples/pkg/generated/clientset/versioned
+//	  listersAPIPath: github.com/kcp-dev/code-generator/examples/pkg/generated/listers
+//	  goHeaderFile: ../hack/boilerplate/boilerplate.generatego.txt
+//	  groupVersions:
+//	  - example:v1
+//
+// Instead of listing groupVersions, discoverGroupVersions: true generates the
+// clientsets for the group versions found in the input directory. The packages of
+// the APIs not found at <inputDir>/<group>/<version> are mapped with inputs, such
+// as [example/v1=./example/v1alpha1].
+//
+// The relative paths of inputDir, outputDir and goHeaderFile are relative to the
+// directory of the file, unlike the ones given on the command line.
+type Config struct {
+	Clientsets []*Clientset
+}
+
+// Clientset describes the generation of one clientset in a Config.
+type Clientset struct {
+	// Generators are the names of the generators to run, as given as argument
+	// on the command line.
+	Generators []string
+	// Flags are the values of the flags set in the file.
+	Flags Flags
+
+	// file is the path of the file the clientset is read from.
+	file string
+	// path is the path of the clientset in the file, such as clientsets[0].
+	path string
+	// keys are the nodes of the keys set for the clientset, to locate errors.
+	keys map[string]*yaml.Node
+	// node is the node of the clientset.
+	node *yaml.Node
+}
+
+// clientsetKeys maps the keys of a clientset in the file to the flag each of them sets.
+var clientsetKeys = map[string]string{
+	"name":                  "clientset-name",
+	"generators":            "",
+	"inputDir":              "input-dir",
+	"outputDir":             "output-dir",
+	"clientsetAPIPath":      "clientset-api-path",
+	"listersAPIPath":        "listers-api-path",
+	"groupVersions":         "group-versions",
+	"inputs":                "input",
+	"goHeaderFile":          "go-header-file",
+	"discoverGroupVersions": "discover-group-versions",
+}
+
+// ConfigError is an error in a config file, located at the offending key.
+type ConfigError struct {
+	// File is the path of the config file.
+	File string
+	// Line and Column locate the key in the file.
+	Line, Column int
+	// Key is the path of the key, such as clientsets[0].groupVersions[1].
+	Key string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %v", e.File, e.Line, e.Column, e.Key, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// LoadConfig reads and validates the config file at the given path.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	return ParseConfig(path, content)
+}
+
+// ParseConfig parses and validates the content of a config file. The path of the
+// file is used in errors, and to resolve the relative paths of the file.
+func ParseConfig(path string, content []byte) (*Config, error) {
+	var doc yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: no clientsets are defined", path)
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	root := &doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, configErrorf(path, root, "", "expected a mapping")
+	}
+
+	c := &Config{}
+	var clientsets *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "clientsets" {
+			return nil, configErrorf(path, key, key.Value, "unknown key")
+		}
+		if value.Kind != yaml.SequenceNode {
+			return nil, configErrorf(path, value, key.Value, "expected a list of clientsets")
+		}
+		clientsets = value
+	}
+	if clientsets == nil || len(clientsets.Content) == 0 {
+		return nil, configErrorf(path, root, "clientsets", "no clientsets are defined")
+	}
+
+	for i, node := range clientsets.Content {
+		cs, err := parseClientset(path, fmt.Sprintf("clientsets[%d]", i), node)
+		if err != nil {
+			return nil, err
+		}
+		c.Clientsets = append(c.Clientsets, cs)
+	}
+
+	// clientsets written to the same place would overwrite each other.
+	seen := map[string]string{}
+	for _, cs := range c.Clientsets {
+		if cs.Flags.ClientsetName == "" {
+			continue
+		}
+		outputDir := cs.Flags.OutputDir
+		if outputDir == "" {
+			outputDir = defaultOutputDir
+		}
+		output := filepath.Join(outputDir, cs.Flags.ClientsetName)
+		if other, ok := seen[output]; ok {
+			return nil, cs.Errorf("name", "clientset %q is already generated to %q by %s", cs.Flags.ClientsetName, outputDir, other)
+		}
+		seen[output] = cs.path
+	}
+	return c, nil
+}
+
+// parseClientset parses the node of a clientset, found at the given path of the file.
+func parseClientset(file, path string, node *yaml.Node) (*Clientset, error) {
+	cs := &Clientset{file: file, path: path, keys: map[string]*yaml.Node{}, node: node}
+	if node.Kind != yaml.MappingNode {
+		return nil, configErrorf(file, node, path, "expected a mapping")
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := path + "." + key.Value
+		if _, ok := clientsetKeys[key.Value]; !ok {
+			return nil, configErrorf(file, key, keyPath, "unknown key")
+		}
+		if _, ok := cs.keys[key.Value]; ok {
+			return nil, configErrorf(file, key, keyPath, "key is set more than once")
+		}
+		cs.keys[key.Value] = key
+
+		var err error
+		switch key.Value {
+		case "name":
+			cs.Flags.ClientsetName, err = scalar(file, keyPath, value)
+		case "generators":
+			cs.Generators, err = sequence(file, keyPath, value)
+		case "inputDir":
+			cs.Flags.InputDir, err = relativePath(file, keyPath, value)
+		case "outputDir":
+			cs.Flags.OutputDir, err = relativePath(file, keyPath, value)
+		case "clientsetAPIPath":
+			cs.Flags.ClientsetAPIPath, err = scalar(file, keyPath, value)
+		case "listersAPIPath":
+			cs.Flags.ListersAPIPath, err = scalar(file, keyPath, value)
+		case "goHeaderFile":
+			cs.Flags.GoHeaderFilePath, err = relativePath(file, keyPath, value)
+		case "discoverGroupVersions":
+			cs.Flags.DiscoverGroupVersions, err = boolean(file, keyPath, value)
+		case "groupVersions":
+			cs.Flags.GroupVersions, err = sequence(file, keyPath, value)
+			if err != nil {
+				break
+			}
+			for j, gv := range cs.Flags.GroupVersions {
+				parts := strings.Split(gv, ":")
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return nil, configErrorf(file, value.Content[j], fmt.Sprintf("%s[%d]", keyPath, j), "%q is not in <group>:<version> format, ex: rbac:v1", gv)
+				}
+			}
+		case "inputs":
+			cs.Flags.Inputs, err = sequence(file, keyPath, value)
+			if err != nil {
+				break
+			}
+			for j, input := range cs.Flags.Inputs {
+				gv, path, ok := strings.Cut(input, "=")
+				group, version, _ := strings.Cut(gv, "/")
+				if !ok || group == "" || version == "" || path == "" {
+					return nil, configErrorf(file, value.Content[j], fmt.Sprintf("%s[%d]", keyPath, j), "%q is not in <group>/<version>=<path> format, ex: rbac/v1=./rbac/v1", input)
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cs.Flags.GroupVersions) != 0 && cs.Flags.DiscoverGroupVersions {
+		return nil, cs.Errorf("discoverGroupVersions", "group versions cannot be both listed and discovered")
+	}
+	return cs, nil
+}
+
+// Errorf returns an error located at the given key of the clientset, or at the
+// clientset itself when the key is not set.
+func (cs *Clientset) Errorf(key, format string, args ...interface{}) error {
+	if node, ok := cs.keys[key]; ok {
+		return configErrorf(cs.file, node, cs.path+"."+key, format, args...)
+	}
+	return configErrorf(cs.file, cs.node, cs.path, format, args...)
+}
+
+// Merge returns the flags of the clientset, overridden by the flags explicitly set
+// on the command line. Flags set neither in the file nor on the command line keep
+// their defaults.
+func (cs *Clientset) Merge(cli Flags, flagset *pflag.FlagSet) Flags {
+	f := cli
+	set := func(key string, dst *string, value string) {
+		if _, ok := cs.keys[key]; ok && !flagset.Changed(clientsetKeys[key]) {
+			*dst = value
+		}
+	}
+	set("name", &f.ClientsetName, cs.Flags.ClientsetName)
+	set("inputDir", &f.InputDir, cs.Flags.InputDir)
+	set("outputDir", &f.OutputDir, cs.Flags.OutputDir)
+	set("clientsetAPIPath", &f.ClientsetAPIPath, cs.Flags.ClientsetAPIPath)
+	set("listersAPIPath", &f.ListersAPIPath, cs.Flags.ListersAPIPath)
+	set("goHeaderFile", &f.GoHeaderFilePath, cs.Flags.GoHeaderFilePath)
+	// listing the group versions on the command line overrides their discovery,
+	// and the other way around.
+	gvsChanged := flagset.Changed(clientsetKeys["groupVersions"]) || flagset.Changed(clientsetKeys["discoverGroupVersions"])
+	if _, ok := cs.keys["groupVersions"]; ok && !gvsChanged {
+		f.GroupVersions = cs.Flags.GroupVersions
+	}
+	if _, ok := cs.keys["discoverGroupVersions"]; ok && !gvsChanged {
+		f.DiscoverGroupVersions = cs.Flags.DiscoverGroupVersions
+	}
+	if _, ok := cs.keys["inputs"]; ok && !flagset.Changed(clientsetKeys["inputs"]) {
+		f.Inputs = cs.Flags.Inputs
+	}
+	f.ConfigFile = cli.ConfigFile
+	return f
+}
+
+// scalar returns the value of a string node.
+func scalar(file, path string, node *yaml.Node) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", configErrorf(file, node, path, "expected a string")
+	}
+	return node.Value, nil
+}
+
+// relativePath returns the value of a string node holding a path, which is resolved
+// against the directory of the file when it is relative.
+func relativePath(file, path string, node *yaml.Node) (string, error) {
+	value, err := scalar(file, path, node)
+	if err != nil || value == "" || filepath.IsAbs(value) {
+		return value, err
+	}
+	return filepath.Join(filepath.Dir(file), value), nil
+}
+
+// boolean returns the value of a boolean node.
+func boolean(file, path string, node *yaml.Node) (bool, error) {
+	var value bool
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		return false, configErrorf(file, node, path, "expected a boolean")
+	}
+	return value, nil
+}
+
+// sequence returns the values of a list of strings node.
+func sequence(file, path string, node *yaml.Node) ([]string, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, configErrorf(file, node, path, "expected a list of strings")
+	}
+	values := make([]string, 0, len(node.Content))
+	for i, item := range node.Content {
+		value, err := scalar(file, fmt.Sprintf("%s[%d]", path, i), item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func configErrorf(file string, node *yaml.Node, key, format string, args ...interface{}) error {
+	return &ConfigError{File: file, Line: node.Line, Column: node.Column, Key: key, Err: fmt.Errorf(format, args...)}
+}
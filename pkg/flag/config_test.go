@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flag
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test config file", func() {
+	const config = `clientsets:
+- name: clusterclient
+  generators: [client, lister]
+  inputDir: ./pkg/apis
+  outputDir: ./pkg
+  clientsetAPIPath: example.com/pkg/generated/clientset/versioned
+  groupVersions:
+  - apps:v1
+  - rbac:v1
+  inputs: [apps/v1=./apps/v1alpha1]
+- name: other
+  inputDir: ./other/apis
+  groupVersions: [batch:v1]
+`
+
+	It("parses multiple clientsets", func() {
+		c, err := ParseConfig("examples/codegen.yaml", []byte(config))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Clientsets).To(HaveLen(2))
+		Expect(c.Clientsets[0].Generators).To(Equal([]string{"client", "lister"}))
+		Expect(c.Clientsets[0].Flags).To(Equal(Flags{
+			ClientsetName:    "clusterclient",
+			InputDir:         "examples/pkg/apis",
+			OutputDir:        "examples/pkg",
+			ClientsetAPIPath: "example.com/pkg/generated/clientset/versioned",
+			GroupVersions:    []string{"apps:v1", "rbac:v1"},
+			Inputs:           []string{"apps/v1=./apps/v1alpha1"},
+		}))
+		Expect(c.Clientsets[1].Flags.GroupVersions).To(Equal([]string{"batch:v1"}))
+	})
+
+	It("overrides the file with the flags set on the command line", func() {
+		c, err := ParseConfig("codegen.yaml", []byte(config))
+		Expect(err).NotTo(HaveOccurred())
+
+		cli := Flags{}
+		flagset := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		cli.AddTo(flagset)
+		Expect(flagset.Parse([]string{"--output-dir", "./out", "--group-versions", "apps:v2"})).To(Succeed())
+
+		f := c.Clientsets[0].Merge(cli, flagset)
+		Expect(f.OutputDir).To(Equal("./out"))
+		Expect(f.GroupVersions).To(Equal([]string{"apps:v2"}))
+		Expect(f.InputDir).To(Equal("pkg/apis"))
+		Expect(f.ClientsetName).To(Equal("clusterclient"))
+
+		// values set neither in the file nor on the command line keep their defaults.
+		f = c.Clientsets[1].Merge(cli, flagset)
+		Expect(f.ClientsetAPIPath).To(Equal("/apis"))
+		Expect(f.InputDir).To(Equal("other/apis"))
+	})
+
+	It("resolves the relative paths against the directory of the file", func() {
+		c, err := ParseConfig("examples/codegen.yaml", []byte("clientsets:\n- inputDir: /apis\n  outputDir: ../pkg\n  goHeaderFile: hack/header.txt\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Clientsets[0].Flags.InputDir).To(Equal("/apis"))
+		Expect(c.Clientsets[0].Flags.OutputDir).To(Equal("pkg"))
+		Expect(c.Clientsets[0].Flags.GoHeaderFilePath).To(Equal("examples/hack/header.txt"))
+	})
+
+	DescribeTable("points errors at the offending key",
+		func(content, expected string) {
+			_, err := ParseConfig("codegen.yaml", []byte(content))
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("unknown top-level key", "clientset: []\n",
+			"codegen.yaml:1:1: clientset: unknown key"),
+		Entry("no clientsets", "clientsets: []\n",
+			"codegen.yaml:1:1: clientsets: no clientsets are defined"),
+		Entry("unknown clientset key", "clientsets:\n- name: a\n  inputdir: b\n",
+			"codegen.yaml:3:3: clientsets[0].inputdir: unknown key"),
+		Entry("wrong value kind", "clientsets:\n- name: [a]\n",
+			"codegen.yaml:2:9: clientsets[0].name: expected a string"),
+		Entry("invalid group version", "clientsets:\n- name: a\n  groupVersions:\n  - apps:v1\n  - rbac\n",
+			`codegen.yaml:5:5: clientsets[0].groupVersions[1]: "rbac" is not in <group>:<version> format, ex: rbac:v1`),
+		Entry("invalid input", "clientsets:\n- name: a\n  inputs: [apps/v1]\n",
+			`codegen.yaml:3:12: clientsets[0].inputs[0]: "apps/v1" is not in <group>/<version>=<path> format, ex: rbac/v1=./rbac/v1`),
+		Entry("group versions both listed and discovered", "clientsets:\n- groupVersions: [apps:v1]\n  discoverGroupVersions: true\n",
+			"codegen.yaml:3:3: clientsets[0].discoverGroupVersions: group versions cannot be both listed and discovered"),
+		Entry("not a boolean", "clientsets:\n- discoverGroupVersions: maybe\n",
+			"codegen.yaml:2:26: clientsets[0].discoverGroupVersions: expected a boolean"),
+		Entry("clientsets generated to the same place", "clientsets:\n- name: a\n- outputDir: ''\n  name: a\n",
+			`codegen.yaml:4:3: clientsets[1].name: clientset "a" is already generated to "output" by clientsets[0]`),
+		Entry("clientsets generated to the default output directory", "clientsets:\n- name: a\n- outputDir: ./output\n  name: a\n",
+			`codegen.yaml:4:3: clientsets[1].name: clientset "a" is already generated to "output" by clientsets[0]`),
+	)
+})
+
+func TestConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Test flag suite")
+}
@@ -20,6 +20,9 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// defaultOutputDir is the output directory when --output-dir is not set.
+const defaultOutputDir = "output"
+
 // Flags - Options accepted by generator
 type Flags struct {
 	// OutputDir is where the generated code is to be written to.
@@ -28,21 +31,51 @@ type Flags struct {
 	InputDir string
 	// ClientsetAPIPath is the path to where client sets are scaffolded by codegen.
 	ClientsetAPIPath string
+	// ListersAPIPath is the path to where listers are scaffolded by codegen.
+	ListersAPIPath string
 	// List of group versions for which the wrappers are to be generated.
 	GroupVersions []string
+	// Inputs map group versions to the packages of their APIs, in the
+	// <group>/<version>=<path> format.
+	Inputs []string
+	// DiscoverGroupVersions generates the wrappers for the group versions found
+	// in the input directory, instead of the listed ones.
+	DiscoverGroupVersions bool
 	// Path to the headerfile.
 	GoHeaderFilePath string
 	// ClientsetName is the name of the clientset to be generated.
 	ClientsetName string
+	// ConfigFile is the path to a config file describing the clientsets to be
+	// generated, whose values are overridden by the other flags.
+	ConfigFile string
+	// Verify compares the generated code with the files on disk instead of
+	// writing them.
+	Verify bool
+	// Parallelism is the number of group versions generated concurrently.
+	Parallelism int
+	// Force overwrites the files of the output directory which were not
+	// generated.
+	Force bool
+	// PruneDryRun lists the previously generated files which are no longer
+	// produced instead of deleting them.
+	PruneDryRun bool
 }
 
 func (f *Flags) AddTo(flagset *pflag.FlagSet) {
 	// TODO: Figure out if its worth defaulting it to pkg/api/...
 	flagset.StringVar(&f.InputDir, "input-dir", "", "Input directory where types are defined. It is assumed that 'types.go' is present inside <InputDir>/pkg/apis.")
-	flagset.StringVar(&f.OutputDir, "output-dir", "output", "Output directory where wrapped clients will be generated. The wrappers will be present in '<output-dir>/generated' path.")
+	flagset.StringVar(&f.OutputDir, "output-dir", defaultOutputDir, "Output directory where wrapped clients will be generated. The wrappers will be present in '<output-dir>/generated' path.")
 	flagset.StringVar(&f.ClientsetAPIPath, "clientset-api-path", "/apis", "package path where clients are generated.")
+	flagset.StringVar(&f.ListersAPIPath, "listers-api-path", "", "package path where listers are generated.")
 
 	flagset.StringArrayVar(&f.GroupVersions, "group-versions", []string{}, "specify group versions for the clients.")
+	flagset.StringArrayVar(&f.Inputs, "input", []string{}, "map a group version to the package of its API, in the <group>/<version>=<path> format with a path relative to the input directory, for APIs not found at <input-dir>/<group>/<version>. It overrides the package of a listed or discovered group version, or adds the group version.")
+	flagset.BoolVar(&f.DiscoverGroupVersions, "discover-group-versions", false, "discover the group versions from the packages of the input directory with a +groupName marker in doc.go and +genclient types, instead of specifying them.")
 	flagset.StringVar(&f.GoHeaderFilePath, "go-header-file", "", "path to headerfile for the generated text.")
 	flagset.StringVar(&f.ClientsetName, "clientset-name", "clientset", "the name of the generated clientset package.")
+	flagset.BoolVar(&f.Verify, "verify", false, "only verify that the generated files are up to date, printing the diff of the stale ones, without writing anything.")
+	flagset.IntVar(&f.Parallelism, "parallelism", 0, "the number of group versions generated concurrently. It defaults to the number of CPUs.")
+	flagset.BoolVar(&f.Force, "force", false, "overwrite the files of the output directory which do not have the header of generated files.")
+	flagset.BoolVar(&f.PruneDryRun, "prune-dry-run", false, "only list the previously generated files which are no longer produced, instead of deleting them.")
+	flagset.StringVar(&f.ConfigFile, "config", "", "path to a YAML file describing the clientsets to generate. Flags set on the command line override its values.")
 }
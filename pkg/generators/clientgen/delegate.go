@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientgen
+
+import (
+	"fmt"
+	"go/build"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kcp-dev/code-generator/pkg/internal"
+)
+
+// delegate is the typed client package of a group version of the delegate clientset.
+// It is type-checked, so that the method sets of its interfaces are resolved by go/types,
+// including the interfaces they embed from other packages.
+type delegate struct {
+	// pkgPath is the import path of the package.
+	pkgPath string
+	pkg     *types.Package
+}
+
+// loadDelegate type-checks the typed client package of the delegate clientset.
+func (g *Generator) loadDelegate(clientPkgPath string) (*delegate, error) {
+	return loadDelegate(g.inputDir, clientPkgPath)
+}
+
+// loadDelegate type-checks the package with the given pattern, resolved from dir. Its
+// imports are read from the export data built by the go command, with the importer of
+// the go toolchain, so that only the package itself is checked from source.
+func loadDelegate(dir, pattern string) (*delegate, error) {
+	fset := token.NewFileSet()
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedSyntax | packages.NeedImports | packages.NeedExportsFile,
+		Dir:  dir,
+		Fset: fset,
+	}, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package for %s, found %d", pattern, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("error loading %s: %v", pattern, pkg.Errors[0])
+	}
+
+	lookup := func(path string) (io.ReadCloser, error) {
+		imp, ok := pkg.Imports[path]
+		if !ok || imp.ExportFile == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(imp.ExportFile)
+	}
+	config := &types.Config{
+		Importer: importer.ForCompiler(fset, "gc", lookup),
+		Sizes:    types.SizesFor("gc", build.Default.GOARCH),
+	}
+	checked, err := config.Check(pkg.PkgPath, fset, pkg.Syntax, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error type-checking %s: %w", pattern, err)
+	}
+	return &delegate{pkgPath: pkg.PkgPath, pkg: checked}, nil
+}
+
+// interfaceType returns the named interface, or nil if the package has no such interface.
+func (d *delegate) interfaceType(name string) *types.Interface {
+	obj, ok := d.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+	return iface
+}
+
+// applyConfigurationsPath returns the import path of the apply configurations accepted
+// by the Apply methods of the package. It is empty when the clientset was generated
+// without apply configurations.
+func (d *delegate) applyConfigurationsPath() string {
+	for _, name := range d.pkg.Scope().Names() {
+		iface := d.interfaceType(name)
+		if iface == nil {
+			continue
+		}
+		for i := 0; i < iface.NumMethods(); i++ {
+			if m := iface.Method(i); m.Name() == "Apply" {
+				if path := applyConfigurationPkgPath(m.Type().(*types.Signature)); path != "" {
+					return path
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// methods returns the method set of the named interface, such as a <Type>Interface, to
+// be wrapped, sorted by name. It includes the methods of the interfaces it embeds, such
+// as the <Type>Expansion, wherever they are declared. Its types are qualified with the
+// aliases of imports, to which the packages they use are added.
+func (d *delegate) methods(name string, imports *internal.Imports) ([]*internal.Method, error) {
+	iface := d.interfaceType(name)
+	if iface == nil {
+		return nil, fmt.Errorf("interface %s not found in %s", name, d.pkgPath)
+	}
+
+	qualifier := func(pkg *types.Package) string {
+		return imports.Alias(pkg.Path())
+	}
+	var result []*internal.Method
+	for i := 0; i < iface.NumMethods(); i++ {
+		fn := iface.Method(i)
+		result = append(result, newMethod(fn.Name(), fn.Type().(*types.Signature), imports, qualifier))
+	}
+	return result, nil
+}
+
+// newMethod returns the wrapper of the delegate method with the given signature. The
+// first parameter is named ctx when it is a context, and the zero values of the results
+// are kept when the last one is an error, so that a cluster mismatch can be returned
+// whatever the results. The parameters keep their names unless they clash with the ones
+// used by the wrapper.
+func newMethod(name string, sig *types.Signature, imports *internal.Imports, qualifier types.Qualifier) *internal.Method {
+	m := &internal.Method{Name: name}
+
+	m.HasContext = sig.Params().Len() > 0 && isContext(sig.Params().At(0).Type())
+	m.ReturnsError = sig.Results().Len() > 0 && types.Identical(sig.Results().At(sig.Results().Len()-1).Type(), types.Universe.Lookup("error").Type())
+
+	// the names the parameters cannot take, as they are used by the wrapper.
+	taken := map[string]bool{"w": true, "ctx": true, "clusterCtx": true, "err": true}
+	for alias := range imports.ByAlias {
+		taken[alias] = true
+	}
+	for alias := range imports.Reserved() {
+		taken[alias] = true
+	}
+
+	var paramList, argList, resultList []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		v := sig.Params().At(i)
+		typ := types.TypeString(v.Type(), qualifier)
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			typ = "..." + types.TypeString(v.Type().(*types.Slice).Elem(), qualifier)
+		}
+		name := v.Name()
+		switch {
+		case i == 0 && m.HasContext:
+			name = "ctx"
+		case name == "" || name == "_" || taken[name]:
+			name = fmt.Sprintf("arg%d", i)
+		}
+		paramList = append(paramList, name+" "+typ)
+		arg := name
+		if strings.HasPrefix(typ, "...") {
+			arg += "..."
+		}
+		argList = append(argList, arg)
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		t := sig.Results().At(i).Type()
+		resultList = append(resultList, types.TypeString(t, qualifier))
+		if m.ReturnsError && i < sig.Results().Len()-1 {
+			m.ZeroResults += zeroValue(t, qualifier) + ", "
+		}
+	}
+
+	m.Params = strings.Join(paramList, ", ")
+	m.Args = strings.Join(argList, ", ")
+	switch {
+	case len(resultList) == 1:
+		m.Results = resultList[0]
+	case len(resultList) > 0:
+		m.Results = "(" + strings.Join(resultList, ", ") + ")"
+	}
+	return m
+}
+
+// zeroValue returns the expression of the zero value of the type.
+func zeroValue(t types.Type, qualifier types.Qualifier) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct, *types.Array:
+		return types.TypeString(t, qualifier) + "{}"
+	}
+	return "nil"
+}
+
+// isContext returns true if the type is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// applyConfigurationPkgPath returns the package path of the apply configuration
+// accepted by an Apply or ApplyStatus method, which is its second parameter.
+func applyConfigurationPkgPath(sig *types.Signature) string {
+	if sig.Params().Len() < 2 {
+		return ""
+	}
+	ptr, ok := sig.Params().At(1).Type().(*types.Pointer)
+	if !ok {
+		return ""
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path()
+}
@@ -22,13 +22,11 @@ import (
 	"fmt"
 	"go/format"
 	"io"
-	"os"
+	"path"
 	"path/filepath"
 	"sort"
-	"strings"
 
 	"golang.org/x/tools/go/packages"
-	"k8s.io/code-generator/cmd/client-gen/args"
 	"k8s.io/code-generator/cmd/client-gen/types"
 	"sigs.k8s.io/controller-tools/pkg/genall"
 	"sigs.k8s.io/controller-tools/pkg/loader"
@@ -44,8 +42,21 @@ var (
 	ruleDefinition = markers.Must(markers.MakeDefinition("genclient", markers.DescribesType, placeholder{}))
 	// nonNamespacedMarker checks if resource is namespaced or clusterscoped
 	nonNamespacedMarker = markers.Must(markers.MakeDefinition("genclient:nonNamespaced", markers.DescribesType, placeholder{}))
-	// noStatusMarker checks if status is to scaffolded
-	noStatusMarker = markers.Must(markers.MakeDefinition("+genclient:noStatus", markers.DescribesType, placeholder{}))
+
+	// The markers below shape the method set of the delegate clients. The wrappers
+	// derive their methods from those clients, so they are only registered for the
+	// types using them to be parsed.
+
+	// noStatusMarker drops the status verbs of a type
+	noStatusMarker = markers.Must(markers.MakeDefinition("genclient:noStatus", markers.DescribesType, placeholder{}))
+	// skipVerbsMarker lists the verbs which are not generated for a type
+	skipVerbsMarker = markers.Must(markers.MakeDefinition(util.SkipVerbsMarkerName, markers.DescribesType, markers.RawArguments(nil)))
+	// onlyVerbsMarker lists the only verbs which are generated for a type
+	onlyVerbsMarker = markers.Must(markers.MakeDefinition(util.OnlyVerbsMarkerName, markers.DescribesType, markers.RawArguments(nil)))
+	// readonlyMarker restricts the verbs of a type to the read-only ones
+	readonlyMarker = markers.Must(markers.MakeDefinition(util.ReadonlyMarkerName, markers.DescribesType, placeholder{}))
+	// methodMarker declares a custom method of a type, it can be repeated
+	methodMarker = markers.Must(markers.MakeDefinition("genclient:method", markers.DescribesType, markers.RawArguments(nil)))
 )
 
 const (
@@ -53,8 +64,17 @@ const (
 	GeneratorName = "client"
 	// packageName for typed client wrappers.
 	typedPackageName = "typed"
+	// packageName for the fake cluster clientset.
+	fakePackageName = "fake"
 	// name of the file while wrapped clientset is written.
 	clientSetFilename = "clientset.go"
+	// name of the file where the cluster-aware discovery is written.
+	discoveryFilename = "discovery.go"
+	// packageName for the implementation shared by the discovery and the typed client wrappers.
+	internalPackageName = "internal"
+	// name of the file where the implementation shared by the discovery and the
+	// typed client wrappers is written.
+	internalFilename = "roundtripper.go"
 	// extension for go file.
 	extensionGo = ".go"
 )
@@ -66,10 +86,8 @@ type placeholder struct{}
 type Generator struct {
 	// inputDir is the path where types are defined.
 	inputDir string
-	// inputpkgPaths stores details on input directory.
-	inputpkgPaths pkgPaths
-	// outputpkgPaths stores details on output directory.
-	outputpkgPaths pkgPaths
+	// outputPkgPath is the go package of the output directory.
+	outputPkgPath string
 	// output Dir where the wrappers are to be written.
 	outputDir string
 	// path to where generated clientsets are found.
@@ -81,23 +99,13 @@ type Generator struct {
 	// headerText is the header text to be added to generated wrappers.
 	// It is obtained from `--go-header-text` flag.
 	headerText string
-}
-
-// TODO: Store this information in generation context, as other genrators
-// may need this too.
-type pkgPaths struct {
-	// basePacakge path as found in go module.
-	basePackage string
-	// hasGoMod is a way of checking if the go.mod file is present inside
-	// the input directory or not. If present the basepkg path need not be modified
-	// to include the location of input directory. If not, include the location of
-	// all the sub folders provided in the input directory.
-	hasGoMod bool
+	// parallelism is the number of group versions rendered concurrently.
+	parallelism int
 }
 
 func (g Generator) RegisterMarker() (*markers.Registry, error) {
 	reg := &markers.Registry{}
-	if err := markers.RegisterAll(reg, ruleDefinition, nonNamespacedMarker, noStatusMarker); err != nil {
+	if err := markers.RegisterAll(reg, ruleDefinition, nonNamespacedMarker, noStatusMarker, skipVerbsMarker, onlyVerbsMarker, readonlyMarker, methodMarker); err != nil {
 		return nil, fmt.Errorf("error registering markers")
 	}
 	return reg, nil
@@ -107,6 +115,11 @@ func (g Generator) GetName() string {
 	return GeneratorName
 }
 
+// OutputDir returns the directory of the wrapped clientset, <outputDir>/<clientsetName>.
+func (g Generator) OutputDir(f flag.Flags) string {
+	return filepath.Join(f.OutputDir, f.ClientsetName)
+}
+
 // Run validates the input from the flags and sets default values, after which
 // it calls the custom client genrator to create wrappers. If there are any
 // errors while generating interface wrappers, it prints it out.
@@ -143,10 +156,14 @@ func validateFlags(f flag.Flags) error {
 		return errors.New("specifying client API path is required currently.")
 	}
 
-	if len(f.GroupVersions) == 0 {
+	if len(f.GroupVersions) == 0 && !f.DiscoverGroupVersions && len(f.Inputs) == 0 {
 		return errors.New("list of group versions for which the clients are to be generated is required.")
 	}
 
+	if len(f.GroupVersions) != 0 && f.DiscoverGroupVersions {
+		return errors.New("group versions cannot be both listed and discovered.")
+	}
+
 	return nil
 }
 
@@ -155,23 +172,11 @@ func validateFlags(f flag.Flags) error {
 func (g *Generator) setDefaults(f flag.Flags) (err error) {
 	if f.InputDir != "" {
 		g.inputDir = f.InputDir
-		pkg, hasGoMod := util.CurrentPackage(f.InputDir)
-		if len(pkg) == 0 {
-			return fmt.Errorf("error finding the module path for this package %q", f.InputDir)
-		}
-		g.inputpkgPaths = pkgPaths{
-			basePackage: pkg,
-			hasGoMod:    hasGoMod,
-		}
 	}
 	if f.OutputDir != "" {
-		pkg, hasGoMod := util.CurrentPackage(f.OutputDir)
-		if len(pkg) == 0 {
-			return fmt.Errorf("error finding the module path for this package %q", f.OutputDir)
-		}
-		g.outputpkgPaths = pkgPaths{
-			basePackage: pkg,
-			hasGoMod:    hasGoMod,
+		g.outputPkgPath, err = util.PackagePath(f.OutputDir)
+		if err != nil {
+			return err
 		}
 		g.outputDir = f.OutputDir
 	}
@@ -181,53 +186,24 @@ func (g *Generator) setDefaults(f flag.Flags) (err error) {
 	if f.ClientsetName != "" {
 		g.clientsetName = f.ClientsetName
 	}
-	g.headerText, err = getHeaderText(f.GoHeaderFilePath)
+	g.parallelism = f.Parallelism
+	g.headerText, err = util.GetHeaderText(f.GoHeaderFilePath)
 	if err != nil {
 		return err
 	}
 	return g.getGV(f)
 }
 
-// getHeaderText reads the text passed through the file present in the
-// path.
-func getHeaderText(path string) (string, error) {
-	var headertext string
-	if path != "" {
-		headerBytes, err := os.ReadFile(path)
-		if err != nil {
-			return "", err
-		}
-		headertext = string(headerBytes)
-	}
-	return headertext, nil
-}
-
 // getGV parses the Group Versions provided in the input through flags
 // and creates a list of []types.GroupVersions.
 func (g *Generator) getGV(f flag.Flags) error {
-	// Its already validated that list of group versions cannot be empty.
-	gvs := f.GroupVersions
-	for _, gv := range gvs {
-		// arr[0] -> group, arr[1] -> versions
-		arr := strings.Split(gv, ":")
-		if len(arr) != 2 {
-			return fmt.Errorf("input to --group-version must be in <group>:<versions> format, ex: rbac:v1. Got %q", gv)
-		}
-
-		versions := strings.Split(arr[1], ",")
-		for _, v := range versions {
-			// input path is converted to <inputDir>/<group>/<version>.
-			// example for input directory of "k8s.io/client-go/kubernetes/pkg/apis/", it would
-			// be converted to "k8s.io/client-go/kubernetes/pkg/apis/rbac/v1".
-			input := filepath.Join(f.InputDir, arr[0], v)
-			groups := []types.GroupVersions{}
-			builder := args.NewGroupVersionsBuilder(&groups)
-			_ = args.NewGVPackagesValue(builder, []string{input})
-
-			g.groupVersions = append(g.groupVersions, groups...)
-
-		}
+	// Its already validated that list of group versions cannot be empty,
+	// unless they are discovered.
+	gvs, err := util.ResolveGroupVersions(f.InputDir, f.GroupVersions, f.DiscoverGroupVersions, f.Inputs)
+	if err != nil {
+		return err
 	}
+	g.groupVersions = append(g.groupVersions, gvs...)
 	return nil
 }
 
@@ -235,35 +211,29 @@ func (g *Generator) getGV(f flag.Flags) error {
 // Then for each type defined in the input, it recursively wraps the subsequent
 // interfaces to be kcp-aware.
 func (g *Generator) generate(ctx *genall.GenerationContext) error {
-	if err := g.writeWrappedClientSet(); err != nil {
+	if err := g.writeWrappedClientSet(ctx); err != nil {
+		return err
+	}
+	if err := g.writeInternal(ctx); err != nil {
+		return err
+	}
+	if err := g.writeDiscovery(ctx); err != nil {
+		return err
+	}
+	if err := g.writeFakeClientSet(ctx); err != nil {
 		return err
 	}
 	return g.generateSubInterfaces(ctx)
 }
 
-func (g *Generator) writeWrappedClientSet() error {
+func (g *Generator) writeWrappedClientSet(ctx *genall.GenerationContext) error {
 	var out bytes.Buffer
 	if err := g.writeHeader(&out); err != nil {
 		return err
 	}
 
 	// Get the location of the typed wrapped clientset for imports.
-	// Cases handled here, for example the scenarios could be:
-	// Case 1:
-	// if basePkg := k8s.io/kcp-dev; outputPkg := k8s.io/kcp-dev/output/examples
-	// then typedPkgPath is k8s.io/kcp-dev/output/examples/
-	// Case 2:
-	// if basePkg := k8s.io/kcp-dev; outputPkg := ./output/examples
-	// then typedPkgPath is k8s.io/kcp-dev/output/examples/
-	// Case 3:
-	// if basePkg := k8s.io/kcp-dev; outputPkg := .
-	// then typedPkgPath is k8s.io/kcp-dev
-	var typedPkgPath string
-	if !g.outputpkgPaths.hasGoMod {
-		typedPkgPath = filepath.Join(util.GetCleanRealtivePath(g.outputpkgPaths.basePackage, filepath.Clean(g.outputDir)), g.clientsetName)
-	} else {
-		typedPkgPath = filepath.Join(g.outputpkgPaths.basePackage, g.clientsetName)
-	}
+	typedPkgPath := path.Join(g.outputPkgPath, g.clientsetName)
 
 	wrappedInf, err := internal.NewInterfaceWrapper(g.clientSetAPIPath, g.clientsetName, typedPkgPath, g.groupVersions, &out)
 	if err != nil {
@@ -281,33 +251,85 @@ func (g *Generator) writeWrappedClientSet() error {
 		outBytes = formattedBytes
 	}
 
-	return g.writeContent(outBytes, clientSetFilename, filepath.Join(g.outputDir, g.clientsetName))
+	return util.WriteContent(ctx.OutputRule, outBytes, clientSetFilename, filepath.Join(g.outputDir, g.clientsetName))
 }
 
-// wrtieContents creates a new file under the path <outputDir>/generated with
-// the specified filename and write contents to it.
-func (g *Generator) writeContent(outBytes []byte, filename string, path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		err = os.MkdirAll(path, 0755)
-		if err != nil {
-			return err
-		}
+// writeInternal writes the implementation shared by the discovery and the typed client
+// wrappers to <outputDir>/<clientsetName>/internal.
+func (g *Generator) writeInternal(ctx *genall.GenerationContext) error {
+	var out bytes.Buffer
+	if err := g.writeHeader(&out); err != nil {
+		return err
 	}
 
-	outputFile, err := os.Create(filepath.Join(path, filename))
+	typedPkgPath := path.Join(g.outputPkgPath, g.clientsetName)
+
+	wrappedInf, err := internal.NewInterfaceWrapper(g.clientSetAPIPath, g.clientsetName, typedPkgPath, g.groupVersions, &out)
 	if err != nil {
 		return err
 	}
-	defer outputFile.Close()
 
-	n, err := outputFile.Write(outBytes)
+	if err := wrappedInf.WriteInternalContent(); err != nil {
+		return err
+	}
+	outBytes, err := format.Source(out.Bytes())
 	if err != nil {
 		return err
 	}
-	if n < len(outBytes) {
+
+	return util.WriteContent(ctx.OutputRule, outBytes, internalFilename, filepath.Join(g.outputDir, g.clientsetName, internalPackageName))
+}
+
+// writeDiscovery writes the discovery clients scoped to a logical cluster, used by the
+// wrapped clientset, to <outputDir>/<clientsetName>.
+func (g *Generator) writeDiscovery(ctx *genall.GenerationContext) error {
+	var out bytes.Buffer
+	if err := g.writeHeader(&out); err != nil {
 		return err
 	}
-	return nil
+
+	typedPkgPath := path.Join(g.outputPkgPath, g.clientsetName)
+
+	wrappedInf, err := internal.NewInterfaceWrapper(g.clientSetAPIPath, g.clientsetName, typedPkgPath, g.groupVersions, &out)
+	if err != nil {
+		return err
+	}
+
+	if err := wrappedInf.WriteDiscoveryContent(); err != nil {
+		return err
+	}
+	outBytes, err := format.Source(out.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return util.WriteContent(ctx.OutputRule, outBytes, discoveryFilename, filepath.Join(g.outputDir, g.clientsetName))
+}
+
+// writeFakeClientSet writes a fake cluster clientset, backed by the fake
+// clientset generated by k8s.io/code-gen, to <outputDir>/<clientsetName>/fake.
+func (g *Generator) writeFakeClientSet(ctx *genall.GenerationContext) error {
+	var out bytes.Buffer
+	if err := g.writeHeader(&out); err != nil {
+		return err
+	}
+
+	clientsetPkgPath := path.Join(g.outputPkgPath, g.clientsetName)
+
+	wrappedInf, err := internal.NewInterfaceWrapper(g.clientSetAPIPath, g.clientsetName, clientsetPkgPath, g.groupVersions, &out)
+	if err != nil {
+		return err
+	}
+
+	if err := wrappedInf.WriteFakeContent(); err != nil {
+		return err
+	}
+	outBytes, err := format.Source(out.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return util.WriteContent(ctx.OutputRule, outBytes, clientSetFilename, filepath.Join(g.outputDir, g.clientsetName, fakePackageName))
 }
 
 func (g *Generator) writeHeader(out io.Writer) error {
@@ -323,107 +345,130 @@ func (g *Generator) writeHeader(out io.Writer) error {
 }
 
 func (g *Generator) generateSubInterfaces(ctx *genall.GenerationContext) error {
-	for _, gv := range g.groupVersions {
-		// Each types.GroupVersions will have only one version.
-		// Even if there are multiple versions for same group, we will have separate types.GroupVersions
-		// for it. Hence length of gv.Versions will always be one.
-		version := gv.Versions[0]
-
-		// This is to accomodate the usecase wherein the apis are defined under a sub-folder inside
-		// base package.
-		basePkg := g.inputpkgPaths.basePackage
-		if !g.inputpkgPaths.hasGoMod {
-			cleanPkgPath := util.CleanInputDir(g.inputDir)
-			if cleanPkgPath != "" {
-				basePkg = filepath.Join(g.inputpkgPaths.basePackage, cleanPkgPath)
-			}
-		}
+	return util.RenderGroupVersions(ctx, g.groupVersions, g.parallelism, func(gv types.GroupVersions) (*util.RenderedGroupVersion, error) {
+		return g.renderSubInterfaces(ctx, gv)
+	})
+}
 
-		path := filepath.Join(basePkg, gv.Group.String(), string(version.Version))
+// renderSubInterfaces renders the wrappers of the typed clients of a group version.
+// It is called concurrently for the group versions.
+func (g *Generator) renderSubInterfaces(ctx *genall.GenerationContext, gv types.GroupVersions) (*util.RenderedGroupVersion, error) {
+	// Each types.GroupVersions will have only one version.
+	// Even if there are multiple versions for same group, we will have separate types.GroupVersions
+	// for it. Hence length of gv.Versions will always be one.
+	version := gv.Versions[0]
+	group, err := internal.NewGroup(gv)
+	if err != nil {
+		return nil, err
+	}
 
-		pkgs, err := loader.LoadRootsWithConfig(&packages.Config{Dir: g.inputDir}, path)
-		if err != nil {
-			return err
-		}
+	// The API package is loaded from its directory, which is not necessarily
+	// <inputDir>/<group>/<version> when mapped with --input.
+	pkgs, err := loader.LoadRootsWithConfig(&packages.Config{Dir: version.Package}, ".")
+	if err != nil {
+		return nil, err
+	}
 
-		// Assign the pkgs obtained from loading roots to generation context.
-		// TODO: Figure out if controller-tools generation runtime can be used to
-		// wire in instead.
-		ctx.Roots = pkgs
+	// The wrappers implement the typed clients of the delegate clientset,
+	// whose method sets are read from its package.
+	clientPkgPath := path.Join(g.clientSetAPIPath, typedPackageName, group.PackageName, string(version.Version))
+	d, err := g.loadDelegate(clientPkgPath)
+	if err != nil {
+		return nil, err
+	}
+	applyPkgPath := d.applyConfigurationsPath()
+	internalPkgPath := path.Join(g.outputPkgPath, g.clientsetName, internalPackageName)
 
-		for _, root := range pkgs {
-			root.NeedTypesInfo()
+	// The roots are assigned to the generation context once every group
+	// version is rendered, to print their errors.
+	// TODO: Figure out if controller-tools generation runtime can be used to
+	// wire in instead.
+	rendered := &util.RenderedGroupVersion{Roots: pkgs}
 
-			// this is to accomodate multiple types defined in single group
-			byType := make(map[string][]byte)
+	for _, root := range pkgs {
+		root.NeedTypesInfo()
+		path := root.PkgPath
 
-			var outCommonContent bytes.Buffer
-			pkgmg := internal.NewPackages(root, path, g.clientSetAPIPath, string(version.Version), gv.PackageName, &outCommonContent)
+		// this is to accomodate multiple types defined in single group
+		byType := make(map[string][]byte)
 
-			if err := g.writeHeader(&outCommonContent); err != nil {
-				root.AddError(err)
-			}
-			err = pkgmg.WriteContent()
-			if err != nil {
-				root.AddError(err)
-			}
+		// the packages used by the methods of all the types, as the common
+		// content only imports those.
+		imports := internal.NewImports(group, string(version.Version), path, clientPkgPath, applyPkgPath, internalPkgPath)
 
-			if eachTypeErr := markers.EachType(ctx.Collector, root, func(info *markers.TypeInfo) {
-				var outContent bytes.Buffer
-
-				// if not enabled for this type, skip
-				if !isEnabledForMethod(info) {
-					return
-				}
-
-				a, err := internal.NewAPI(root, info, string(version.Version), gv.PackageName, !isClusterScoped(info), hasStatusSubresource(info), &outContent)
-				if err != nil {
-					root.AddError(err)
-					return
-				}
-
-				err = a.WriteContent()
-				if err != nil {
-					root.AddError(err)
-					return
-				}
-
-				outBytes := outContent.Bytes()
-				if len(outBytes) > 0 {
-					byType[info.Name] = outBytes
-				}
-			}); eachTypeErr != nil {
-				return eachTypeErr
-			}
+		if eachTypeErr := markers.EachType(ctx.Collector, root, func(info *markers.TypeInfo) {
+			var outContent bytes.Buffer
 
-			if len(byType) == 0 {
-				return nil
+			// if not enabled for this type, skip
+			if !isEnabledForMethod(info) {
+				return
 			}
 
-			var outContent bytes.Buffer
-			outContent.Write(outCommonContent.Bytes())
-			err = writeMethods(&outContent, byType)
+			methods, err := d.methods(info.Name+"Interface", imports)
 			if err != nil {
-				return err
+				root.AddError(fmt.Errorf("type %s: %w", info.Name, err))
+				return
 			}
 
-			outBytes := outContent.Bytes()
-			formattedBytes, err := format.Source(outBytes)
+			a, err := internal.NewAPI(root, info, string(version.Version), group, !isClusterScoped(info), methods, &outContent)
 			if err != nil {
 				root.AddError(err)
-			} else {
-				outBytes = formattedBytes
+				return
 			}
 
-			filename := gv.Group.PackageName() + string(version.Version) + extensionGo
-			err = g.writeContent(outBytes, filename, filepath.Join(g.outputDir, g.clientsetName, typedPackageName, gv.Group.PackageName(), string(version.Version)))
+			err = a.WriteContent()
 			if err != nil {
 				root.AddError(err)
-				return err
+				return
 			}
+
+			outBytes := outContent.Bytes()
+			if len(outBytes) > 0 {
+				byType[info.Name] = outBytes
+			}
+		}); eachTypeErr != nil {
+			return nil, eachTypeErr
+		}
+
+		if len(byType) == 0 {
+			continue
+		}
+
+		var outContent bytes.Buffer
+		pkgmg := internal.NewPackages(root, path, g.clientSetAPIPath, string(version.Version), group, &outContent)
+		pkgmg.ApplyConfigurationsPath = applyPkgPath
+		pkgmg.Imports = imports
+		pkgmg.Types = sortedTypes(byType)
+		pkgmg.InternalPath = internalPkgPath
+
+		if err := g.writeHeader(&outContent); err != nil {
+			root.AddError(err)
 		}
+		err = pkgmg.WriteContent()
+		if err != nil {
+			root.AddError(err)
+		}
+
+		err = writeMethods(&outContent, byType)
+		if err != nil {
+			return nil, err
+		}
+
+		outBytes := outContent.Bytes()
+		formattedBytes, err := format.Source(outBytes)
+		if err != nil {
+			root.AddError(err)
+		} else {
+			outBytes = formattedBytes
+		}
+
+		rendered.Files = append(rendered.Files, util.GeneratedFile{
+			Dir:      filepath.Join(g.outputDir, g.clientsetName, typedPackageName, group.PackageName, string(version.Version)),
+			Filename: group.PackageName + string(version.Version) + extensionGo,
+			Content:  outBytes,
+		})
 	}
-	return nil
+	return rendered, nil
 }
 
 // isEnabledForMethod verifies if the genclient marker is enabled for
@@ -440,32 +485,18 @@ func isClusterScoped(info *markers.TypeInfo) bool {
 	return enabled != nil
 }
 
-// hasStatusSubresource verifies if updateStatus verb is to be scaffolded.
-// if `noStatus` marker is present is returns false. Else it checks if
-// the type has Status field.
-func hasStatusSubresource(info *markers.TypeInfo) bool {
-	if info.Markers.Get(noStatusMarker.Name) != nil {
-		return false
-	}
-
-	hasStatusField := false
-	for _, f := range info.Fields {
-		if f.Name == "Status" {
-			hasStatusField = true
-			break
-		}
-	}
-	return hasStatusField
-}
-
-func writeMethods(out io.Writer, byType map[string][]byte) error {
+// sortedTypes returns the names of the types of the wrapped methods, sorted.
+func sortedTypes(byType map[string][]byte) []string {
 	sortedNames := make([]string, 0, len(byType))
 	for name := range byType {
 		sortedNames = append(sortedNames, name)
 	}
 	sort.Strings(sortedNames)
+	return sortedNames
+}
 
-	for _, name := range sortedNames {
+func writeMethods(out io.Writer, byType map[string][]byte) error {
+	for _, name := range sortedTypes(byType) {
 		_, err := out.Write(byType[name])
 		if err != nil {
 			return err
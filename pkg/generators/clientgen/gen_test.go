@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/kcp-dev/code-generator/pkg/flag"
+	"github.com/kcp-dev/code-generator/pkg/internal"
 )
 
 // TODO: tests are currently broken as they expect a "test" input directory that does not exist.
@@ -181,6 +182,136 @@ var _ = XDescribe("Test generator funcs", func() {
 	})
 })
 
+var _ = Describe("Test delegate methods", func() {
+	const (
+		testdata  = "github.com/kcp-dev/code-generator/pkg/generators/clientgen/testdata"
+		apiPath   = testdata + "/apis/example/v1"
+		applyPath = testdata + "/applyconfigurations/example/v1"
+	)
+	var (
+		d       *delegate
+		imports *internal.Imports
+	)
+	BeforeEach(func() {
+		var err error
+		d, err = loadDelegate(".", "./testdata/clientset/typed/example/v1")
+		Expect(err).NotTo(HaveOccurred())
+		imports = internal.NewImports(internal.Group{PackageName: "example", GoName: "Example"}, "v1", apiPath, d.pkgPath, d.applyConfigurationsPath(), "example.dev/clusterclient/internal")
+	})
+
+	It("should wrap the methods of interfaces embedded from other packages", func() {
+		methods, err := d.methods("TestTypeExpansion", imports)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(methods).To(ContainElement(&internal.Method{
+			Name:         "Evict",
+			Params:       "ctx context.Context, eviction *policyv1.Eviction",
+			Args:         "ctx, eviction",
+			Results:      "error",
+			HasContext:   true,
+			ReturnsError: true,
+		}))
+		Expect(imports.ByAlias).To(HaveKeyWithValue("policyv1", "k8s.io/api/policy/v1"))
+	})
+
+	It("should find the apply configurations", func() {
+		Expect(d.applyConfigurationsPath()).To(Equal(applyPath))
+	})
+
+	It("should wrap every method of the interface and its expansion", func() {
+		methods, err := d.methods("TestTypeInterface", imports)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(methods).To(Equal([]*internal.Method{
+			{
+				Name:         "Apply",
+				Params:       "ctx context.Context, testType *exampleapplyv1.TestTypeApplyConfiguration, opts metav1.ApplyOptions",
+				Args:         "ctx, testType, opts",
+				Results:      "(*exampleapiv1.TestType, error)",
+				ZeroResults:  "nil, ",
+				HasContext:   true,
+				ReturnsError: true,
+			},
+			{
+				Name:         "Evict",
+				Params:       "ctx context.Context, eviction *policyv1.Eviction",
+				Args:         "ctx, eviction",
+				Results:      "error",
+				HasContext:   true,
+				ReturnsError: true,
+			},
+			{
+				Name:         "Get",
+				Params:       "ctx context.Context, name string, opts metav1.GetOptions",
+				Args:         "ctx, name, opts",
+				Results:      "(*exampleapiv1.TestType, error)",
+				ZeroResults:  "nil, ",
+				HasContext:   true,
+				ReturnsError: true,
+			},
+			{
+				Name:         "Items",
+				Params:       "ctx context.Context",
+				Args:         "ctx",
+				Results:      "(*exampleapiv1.List[exampleapiv1.TestType], error)",
+				ZeroResults:  "nil, ",
+				HasContext:   true,
+				ReturnsError: true,
+			},
+			{
+				Name:    "Local",
+				Params:  "c examplev1.TestTypeInterface",
+				Args:    "c",
+				Results: "bool",
+			},
+			{
+				Name:       "Names",
+				Params:     "ctx context.Context",
+				Args:       "ctx",
+				Results:    "[]string",
+				HasContext: true,
+			},
+			{
+				Name:         "Node",
+				Params:       "ctx context.Context, name string",
+				Args:         "ctx, name",
+				Results:      "(*gopkginyamlv3.Node, error)",
+				ZeroResults:  "nil, ",
+				HasContext:   true,
+				ReturnsError: true,
+			},
+			{
+				Name:         "Scale",
+				Params:       "ctx context.Context, name string, scale *autoscalingv1.Scale, arg3 bool, opts ...string",
+				Args:         "ctx, name, scale, arg3, opts...",
+				Results:      "(*exampleapiv1.TestType, error)",
+				ZeroResults:  "nil, ",
+				HasContext:   true,
+				ReturnsError: true,
+			},
+			{
+				Name:         "Status",
+				Params:       "ctx context.Context, name string",
+				Args:         "ctx, name",
+				Results:      "(autoscalingv1.ScaleStatus, string, int32, bool, error)",
+				ZeroResults:  `autoscalingv1.ScaleStatus{}, "", 0, false, `,
+				HasContext:   true,
+				ReturnsError: true,
+			},
+		}))
+		Expect(imports.ByAlias).To(Equal(map[string]string{
+			"autoscalingv1": "k8s.io/api/autoscaling/v1",
+			"gopkginyamlv3": "gopkg.in/yaml.v3",
+			"policyv1":      "k8s.io/api/policy/v1",
+		}))
+		Expect(imports.Uses(applyPath)).To(BeTrue())
+		Expect(imports.Uses("k8s.io/apimachinery/pkg/watch")).To(BeFalse())
+	})
+
+	It("should error when the interface is not found", func() {
+		_, err := d.methods("ClusterTestTypeInterface", imports)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 func TestMetadata(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Test generator suite")
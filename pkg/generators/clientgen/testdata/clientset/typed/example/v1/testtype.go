@@ -0,0 +1,32 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	v1 "github.com/kcp-dev/code-generator/pkg/generators/clientgen/testdata/apis/example/v1"
+	examplev1 "github.com/kcp-dev/code-generator/pkg/generators/clientgen/testdata/applyconfigurations/example/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestTypeInterface is the typed client of TestTypes.
+type TestTypeInterface interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.TestType, error)
+	Apply(ctx context.Context, testType *examplev1.TestTypeApplyConfiguration, opts metav1.ApplyOptions) (result *v1.TestType, err error)
+	TestTypeExpansion
+}
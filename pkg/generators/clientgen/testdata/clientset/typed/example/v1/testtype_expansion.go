@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/kcp-dev/code-generator/pkg/generators/clientgen/testdata/apis/example/v1"
+	"github.com/kcp-dev/code-generator/pkg/generators/clientgen/testdata/expansions/v2"
+	"gopkg.in/yaml.v3"
+	autoscaling "k8s.io/api/autoscaling/v1"
+)
+
+// TestTypeExpansion are the hand-written methods of the typed client.
+type TestTypeExpansion interface {
+	Scale(ctx context.Context, name string, scale *autoscaling.Scale, _ bool, opts ...string) (*TestType, error)
+	Names(ctx context.Context) []string
+	Local(c TestTypeInterface) bool
+	Items(ctx context.Context) (*List[TestType], error)
+	Node(ctx context.Context, name string) (*yaml.Node, error)
+	Status(ctx context.Context, name string) (autoscaling.ScaleStatus, string, int32, bool, error)
+	expansions.Evicter
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expansions
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+// Evicter is an interface shared by the expansions of several typed clients.
+type Evicter interface {
+	Evict(ctx context.Context, eviction *policyv1.Eviction) error
+}
@@ -34,4 +34,8 @@ type Generator interface {
 	RegisterMarker() (*markers.Registry, error)
 	// GetName returns the name of the generator.
 	GetName() string
+	// OutputDir returns the directory the generator writes to given the flags.
+	// It only holds generated files of the generator, so that the ones it no
+	// longer produces are pruned.
+	OutputDir(f flag.Flags) string
 }
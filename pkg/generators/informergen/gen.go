@@ -0,0 +1,339 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informergen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+	"k8s.io/code-generator/cmd/client-gen/types"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+
+	"github.com/kcp-dev/code-generator/pkg/flag"
+	"github.com/kcp-dev/code-generator/pkg/internal"
+	"github.com/kcp-dev/code-generator/pkg/util"
+)
+
+var (
+	// ruleDefinition is a marker for defining rules
+	ruleDefinition = markers.Must(markers.MakeDefinition("genclient", markers.DescribesType, placeholder{}))
+	// nonNamespacedMarker checks if resource is namespaced or clusterscoped
+	nonNamespacedMarker = markers.Must(markers.MakeDefinition("genclient:nonNamespaced", markers.DescribesType, placeholder{}))
+	// skipVerbsMarker lists the verbs which are not generated for a type
+	skipVerbsMarker = markers.Must(markers.MakeDefinition(util.SkipVerbsMarkerName, markers.DescribesType, markers.RawArguments(nil)))
+	// onlyVerbsMarker lists the only verbs which are generated for a type
+	onlyVerbsMarker = markers.Must(markers.MakeDefinition(util.OnlyVerbsMarkerName, markers.DescribesType, markers.RawArguments(nil)))
+	// readonlyMarker restricts the verbs of a type to the read-only ones
+	readonlyMarker = markers.Must(markers.MakeDefinition(util.ReadonlyMarkerName, markers.DescribesType, placeholder{}))
+)
+
+const (
+	// GeneratorName is the name of the generator.
+	GeneratorName = "informer"
+	// packageName for cluster-aware informers.
+	informersPackageName = "informers"
+	// packageName for the interfaces shared by the factory and the informers.
+	internalInterfacesPackageName = "internalinterfaces"
+	// packageName for cluster-aware listers, as written by the lister generator.
+	listersPackageName = "listers"
+	// name of the file where the shared informer factory is written.
+	factoryFilename = "factory.go"
+	// name of the file where the internal interfaces are written.
+	internalInterfacesFilename = "factory_interfaces.go"
+	// extension for go file.
+	extensionGo = ".go"
+)
+
+// Assigning marker's output to a placeholder struct, to verify to
+// typecast the result and make sure if it exists for the type.
+type placeholder struct{}
+
+type Generator struct {
+	// inputDir is the path where types are defined.
+	inputDir string
+	// outputPackage is the go package of the output directory.
+	outputPackage string
+	// output Dir where the informers are to be written.
+	outputDir string
+	// clientsetName is the name of the wrapped clientset package the
+	// informers list and watch through.
+	clientsetName string
+	// GroupVersions for whom the informers are to be generated.
+	groupVersions []types.GroupVersions
+	// headerText is the header text to be added to generated informers.
+	// It is obtained from `--go-header-text` flag.
+	headerText string
+	// parallelism is the number of group versions rendered concurrently.
+	parallelism int
+}
+
+func (g Generator) RegisterMarker() (*markers.Registry, error) {
+	reg := &markers.Registry{}
+	if err := markers.RegisterAll(reg, ruleDefinition, nonNamespacedMarker, skipVerbsMarker, onlyVerbsMarker, readonlyMarker); err != nil {
+		return nil, fmt.Errorf("error registering markers")
+	}
+	return reg, nil
+}
+
+func (g Generator) GetName() string {
+	return GeneratorName
+}
+
+// OutputDir returns the directory of the informers, <outputDir>/informers.
+func (g Generator) OutputDir(f flag.Flags) string {
+	return filepath.Join(f.OutputDir, informersPackageName)
+}
+
+// Run validates the input from the flags and sets default values, after which
+// it generates a cluster-aware shared informer factory and an informer for
+// every type enabled with the genclient marker. The informers list and watch
+// through the wrapped clientset and are backed by the cluster-aware listers,
+// so both the client and lister generators are expected to write to the same
+// output directory.
+func (g Generator) Run(ctx *genall.GenerationContext, f flag.Flags) error {
+	if err := validateFlags(f); err != nil {
+		return err
+	}
+
+	if err := g.setDefaults(f); err != nil {
+		return err
+	}
+	if err := g.generate(ctx); err != nil {
+		return err
+	}
+
+	hadErr := loader.PrintErrors(ctx.Roots, packages.TypeError)
+	if hadErr {
+		return fmt.Errorf("generator did not run successfully")
+	}
+	return nil
+}
+
+// validateFlags checks if the inputs provided through flags are valid.
+func validateFlags(f flag.Flags) error {
+	if f.InputDir == "" {
+		return errors.New("input path to API definition is required.")
+	}
+
+	if f.ClientsetName == "" {
+		return errors.New("name of the wrapped clientset is required to generate informers.")
+	}
+
+	if len(f.GroupVersions) == 0 && !f.DiscoverGroupVersions && len(f.Inputs) == 0 {
+		return errors.New("list of group versions for which the informers are to be generated is required.")
+	}
+
+	if len(f.GroupVersions) != 0 && f.DiscoverGroupVersions {
+		return errors.New("group versions cannot be both listed and discovered.")
+	}
+
+	return nil
+}
+
+// setDefaults sets the default values for the generator. It also creates
+// a list of group versions provided as an input.
+func (g *Generator) setDefaults(f flag.Flags) (err error) {
+	g.inputDir = f.InputDir
+	g.outputDir = f.OutputDir
+	g.outputPackage, err = util.PackagePath(f.OutputDir)
+	if err != nil {
+		return err
+	}
+	g.clientsetName = f.ClientsetName
+
+	g.parallelism = f.Parallelism
+	g.headerText, err = util.GetHeaderText(f.GoHeaderFilePath)
+	if err != nil {
+		return err
+	}
+	g.groupVersions, err = util.ResolveGroupVersions(f.InputDir, f.GroupVersions, f.DiscoverGroupVersions, f.Inputs)
+	return err
+}
+
+// outputPkgPath returns the go package of the given path inside the output
+// directory.
+func (g *Generator) outputPkgPath(elem ...string) string {
+	return path.Join(append([]string{g.outputPackage}, elem...)...)
+}
+
+// generate writes one file of informers per group version to
+// <outputDir>/informers/<group>/<version>/<group><version>.go, and the shared
+// informer factory of these group versions to <outputDir>/informers/factory.go.
+// The group versions without a type which can be listed and watched have no
+// informers, so they are left out of the factory.
+func (g *Generator) generate(ctx *genall.GenerationContext) error {
+	clientsetPkgPath := g.outputPkgPath(g.clientsetName)
+	informersPkgPath := g.outputPkgPath(informersPackageName)
+
+	var (
+		lock          sync.Mutex
+		withInformers = make(map[string]bool)
+	)
+	if err := util.RenderGroupVersions(ctx, g.groupVersions, g.parallelism, func(gv types.GroupVersions) (*util.RenderedGroupVersion, error) {
+		rendered, err := g.render(ctx, gv, clientsetPkgPath, informersPkgPath)
+		if err != nil || len(rendered.Files) == 0 {
+			return rendered, err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		withInformers[gv.Versions[0].Package] = true
+		return rendered, nil
+	}); err != nil {
+		return err
+	}
+
+	var groupVersions []types.GroupVersions
+	for _, gv := range g.groupVersions {
+		if withInformers[gv.Versions[0].Package] {
+			groupVersions = append(groupVersions, gv)
+		}
+	}
+
+	var factory bytes.Buffer
+	factory.WriteString(g.headerText)
+	f, err := internal.NewInformerFactory(g.clientsetName, clientsetPkgPath, informersPkgPath, groupVersions, &factory)
+	if err != nil {
+		return err
+	}
+	if err := f.WriteContent(); err != nil {
+		return err
+	}
+	if err := g.writeFormatted(ctx, factory.Bytes(), factoryFilename, filepath.Join(g.outputDir, informersPackageName)); err != nil {
+		return err
+	}
+
+	var interfaces bytes.Buffer
+	interfaces.WriteString(g.headerText)
+	f, err = internal.NewInformerFactory(g.clientsetName, clientsetPkgPath, informersPkgPath, groupVersions, &interfaces)
+	if err != nil {
+		return err
+	}
+	if err := f.WriteInternalInterfacesContent(); err != nil {
+		return err
+	}
+	return g.writeFormatted(ctx, interfaces.Bytes(), internalInterfacesFilename, filepath.Join(g.outputDir, informersPackageName, internalInterfacesPackageName))
+}
+
+// render renders the informers of a group version. It is called concurrently for
+// the group versions.
+func (g *Generator) render(ctx *genall.GenerationContext, gv types.GroupVersions, clientsetPkgPath, informersPkgPath string) (*util.RenderedGroupVersion, error) {
+	// Each types.GroupVersions will have only one version.
+	version := string(gv.Versions[0].Version)
+	group, err := internal.NewGroup(gv)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API package is loaded from its directory, which is not necessarily
+	// <inputDir>/<group>/<version> when mapped with --input.
+	pkgs, err := loader.LoadRootsWithConfig(&packages.Config{Dir: gv.Versions[0].Package}, ".")
+	if err != nil {
+		return nil, err
+	}
+	rendered := &util.RenderedGroupVersion{Roots: pkgs}
+
+	for _, root := range pkgs {
+		root.NeedTypesInfo()
+		path := root.PkgPath
+
+		byType := make(map[string][]byte)
+		if eachTypeErr := markers.EachType(ctx.Collector, root, func(info *markers.TypeInfo) {
+			if info.Markers.Get(ruleDefinition.Name) == nil {
+				return
+			}
+			// Like informer-gen, skip the types whose clients cannot list and watch.
+			verbs, err := util.ClientVerbs(info)
+			if err != nil {
+				root.AddError(err)
+				return
+			}
+			if !verbs["list"] || !verbs["watch"] {
+				return
+			}
+
+			var outContent bytes.Buffer
+			i, err := internal.NewInformer(root, info, g.clientsetName, version, group, info.Markers.Get(nonNamespacedMarker.Name) == nil, &outContent)
+			if err != nil {
+				root.AddError(err)
+				return
+			}
+			if err := i.WriteContent(); err != nil {
+				root.AddError(err)
+				return
+			}
+			byType[info.Name] = outContent.Bytes()
+		}); eachTypeErr != nil {
+			return nil, eachTypeErr
+		}
+
+		if len(byType) == 0 {
+			continue
+		}
+
+		sortedNames := make([]string, 0, len(byType))
+		for name := range byType {
+			sortedNames = append(sortedNames, name)
+		}
+		sort.Strings(sortedNames)
+
+		var out bytes.Buffer
+		out.WriteString(g.headerText)
+		listersPkgPath := g.outputPkgPath(listersPackageName, group.PackageName, version)
+		if err := internal.NewInformerPackage(path, listersPkgPath, clientsetPkgPath, informersPkgPath, version, group, sortedNames, &out).WriteContent(); err != nil {
+			return nil, err
+		}
+		for _, name := range sortedNames {
+			out.Write(byType[name])
+		}
+
+		file, err := formatted(out.Bytes(), group.PackageName+version+extensionGo, filepath.Join(g.outputDir, informersPackageName, group.PackageName, version))
+		if err != nil {
+			root.AddError(err)
+			return nil, err
+		}
+		rendered.Files = append(rendered.Files, file)
+	}
+	return rendered, nil
+}
+
+// writeFormatted formats the go source and writes it to the given path.
+func (g *Generator) writeFormatted(ctx *genall.GenerationContext, source []byte, filename, path string) error {
+	file, err := formatted(source, filename, path)
+	if err != nil {
+		return err
+	}
+	return util.WriteContent(ctx.OutputRule, file.Content, file.Filename, file.Dir)
+}
+
+// formatted returns the file with the given name in the directory, with the
+// formatted go source.
+func formatted(source []byte, filename, dir string) (util.GeneratedFile, error) {
+	outBytes, err := format.Source(source)
+	if err != nil {
+		return util.GeneratedFile{}, fmt.Errorf("error formatting %s: %w", filepath.Join(dir, filename), err)
+	}
+	return util.GeneratedFile{Dir: dir, Filename: filename, Content: outBytes}, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informergen
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+
+	"github.com/kcp-dev/code-generator/pkg/flag"
+)
+
+var _ = Describe("Test generator funcs", func() {
+	Describe("Test validate flags", func() {
+		var (
+			f flag.Flags
+		)
+		BeforeEach(func() {
+			f = flag.Flags{}
+			f.InputDir = "test"
+			f.ClientsetName = "clusterclient"
+			f.GroupVersions = []string{"apps:v1"}
+		})
+
+		It("Should not error when input in set right", func() {
+			Expect(validateFlags(f)).NotTo(HaveOccurred())
+		})
+
+		It("verify input path error", func() {
+			f.InputDir = ""
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("input path to API definition is required."))
+		})
+
+		It("verify clientset name", func() {
+			f.ClientsetName = ""
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("name of the wrapped clientset is required to generate informers."))
+		})
+
+		It("verify group version list", func() {
+			f.GroupVersions = []string{}
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("list of group versions for which the informers are to be generated is required."))
+		})
+	})
+})
+
+// memoryOutput keeps the generated files in memory, by path.
+type memoryOutput map[string]*bytes.Buffer
+
+func (o memoryOutput) Open(_ *loader.Package, path string) (io.WriteCloser, error) {
+	o[path] = &bytes.Buffer{}
+	return nopCloser{o[path]}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+var _ = Describe("Test generate", func() {
+	It("should only add the group versions with informers to the factory", func() {
+		g := Generator{}
+		reg, err := g.RegisterMarker()
+		Expect(err).NotTo(HaveOccurred())
+		output := memoryOutput{}
+		ctx := &genall.GenerationContext{Collector: &markers.Collector{Registry: reg}, OutputRule: output}
+
+		Expect(g.Run(ctx, flag.Flags{
+			InputDir:      "testdata/apis",
+			OutputDir:     "testdata",
+			ClientsetName: "clusterclient",
+			GroupVersions: []string{"apps:v1", "batch:v1"},
+		})).To(Succeed())
+
+		Expect(output).To(HaveKey(filepath.Join("testdata", "informers", "apps", "v1", "appsv1.go")))
+		Expect(output).NotTo(HaveKey(filepath.Join("testdata", "informers", "batch", "v1", "batchv1.go")))
+
+		factory := output[filepath.Join("testdata", "informers", "factory.go")].String()
+		Expect(factory).To(ContainSubstring("AppsV1() appsv1.Interface"))
+		Expect(factory).NotTo(ContainSubstring("batch"))
+		Expect(factory).To(ContainSubstring("func NewSharedInformerFactory(client clusterclient.ClusterInterface, "))
+
+		interfaces := output[filepath.Join("testdata", "informers", "internalinterfaces", "factory_interfaces.go")].String()
+		Expect(interfaces).To(ContainSubstring("type NewInformerFunc func(clusterclient.ClusterInterface, time.Duration) cache.SharedIndexInformer"))
+	})
+})
+
+func TestMetadata(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Test informer generator suite")
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listergen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+	"k8s.io/code-generator/cmd/client-gen/types"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+
+	"github.com/kcp-dev/code-generator/pkg/flag"
+	"github.com/kcp-dev/code-generator/pkg/internal"
+	"github.com/kcp-dev/code-generator/pkg/util"
+)
+
+var (
+	// ruleDefinition is a marker for defining rules
+	ruleDefinition = markers.Must(markers.MakeDefinition("genclient", markers.DescribesType, placeholder{}))
+	// nonNamespacedMarker checks if resource is namespaced or clusterscoped
+	nonNamespacedMarker = markers.Must(markers.MakeDefinition("genclient:nonNamespaced", markers.DescribesType, placeholder{}))
+	// skipVerbsMarker lists the verbs which are not generated for a type
+	skipVerbsMarker = markers.Must(markers.MakeDefinition(util.SkipVerbsMarkerName, markers.DescribesType, markers.RawArguments(nil)))
+	// onlyVerbsMarker lists the only verbs which are generated for a type
+	onlyVerbsMarker = markers.Must(markers.MakeDefinition(util.OnlyVerbsMarkerName, markers.DescribesType, markers.RawArguments(nil)))
+	// readonlyMarker restricts the verbs of a type to the read-only ones
+	readonlyMarker = markers.Must(markers.MakeDefinition(util.ReadonlyMarkerName, markers.DescribesType, placeholder{}))
+)
+
+const (
+	// GeneratorName is the name of the generator.
+	GeneratorName = "lister"
+	// packageName for cluster-aware listers.
+	listersPackageName = "listers"
+	// extension for go file.
+	extensionGo = ".go"
+)
+
+// Assigning marker's output to a placeholder struct, to verify to
+// typecast the result and make sure if it exists for the type.
+type placeholder struct{}
+
+type Generator struct {
+	// inputDir is the path where types are defined.
+	inputDir string
+	// output Dir where the listers are to be written.
+	outputDir string
+	// path to where generated listers are found.
+	listersAPIPath string
+	// GroupVersions for whom the listers are to be generated.
+	groupVersions []types.GroupVersions
+	// headerText is the header text to be added to generated listers.
+	// It is obtained from `--go-header-text` flag.
+	headerText string
+	// parallelism is the number of group versions rendered concurrently.
+	parallelism int
+}
+
+func (g Generator) RegisterMarker() (*markers.Registry, error) {
+	reg := &markers.Registry{}
+	if err := markers.RegisterAll(reg, ruleDefinition, nonNamespacedMarker, skipVerbsMarker, onlyVerbsMarker, readonlyMarker); err != nil {
+		return nil, fmt.Errorf("error registering markers")
+	}
+	return reg, nil
+}
+
+func (g Generator) GetName() string {
+	return GeneratorName
+}
+
+// OutputDir returns the directory of the listers, <outputDir>/listers.
+func (g Generator) OutputDir(f flag.Flags) string {
+	return filepath.Join(f.OutputDir, listersPackageName)
+}
+
+// Run validates the input from the flags and sets default values, after which
+// it generates cluster-aware listers for every type enabled with the genclient
+// marker. If there are any errors while generating listers, it prints it out.
+func (g Generator) Run(ctx *genall.GenerationContext, f flag.Flags) error {
+	if err := validateFlags(f); err != nil {
+		return err
+	}
+
+	if err := g.setDefaults(f); err != nil {
+		return err
+	}
+	if err := g.generate(ctx); err != nil {
+		return err
+	}
+
+	hadErr := loader.PrintErrors(ctx.Roots, packages.TypeError)
+	if hadErr {
+		return fmt.Errorf("generator did not run successfully")
+	}
+	return nil
+}
+
+// validateFlags checks if the inputs provided through flags are valid.
+func validateFlags(f flag.Flags) error {
+	if f.InputDir == "" {
+		return errors.New("input path to API definition is required.")
+	}
+
+	if f.ListersAPIPath == "" {
+		return errors.New("specifying listers API path is required to generate listers.")
+	}
+
+	if len(f.GroupVersions) == 0 && !f.DiscoverGroupVersions && len(f.Inputs) == 0 {
+		return errors.New("list of group versions for which the listers are to be generated is required.")
+	}
+
+	if len(f.GroupVersions) != 0 && f.DiscoverGroupVersions {
+		return errors.New("group versions cannot be both listed and discovered.")
+	}
+
+	return nil
+}
+
+// setDefaults sets the default values for the generator. It also creates
+// a list of group versions provided as an input.
+func (g *Generator) setDefaults(f flag.Flags) (err error) {
+	g.inputDir = f.InputDir
+	g.outputDir = f.OutputDir
+	g.listersAPIPath = f.ListersAPIPath
+
+	g.parallelism = f.Parallelism
+	g.headerText, err = util.GetHeaderText(f.GoHeaderFilePath)
+	if err != nil {
+		return err
+	}
+	g.groupVersions, err = util.ResolveGroupVersions(f.InputDir, f.GroupVersions, f.DiscoverGroupVersions, f.Inputs)
+	return err
+}
+
+// generate writes one file of cluster-aware listers per group version, to
+// <outputDir>/listers/<group>/<version>/<group><version>.go.
+func (g *Generator) generate(ctx *genall.GenerationContext) error {
+	return util.RenderGroupVersions(ctx, g.groupVersions, g.parallelism, func(gv types.GroupVersions) (*util.RenderedGroupVersion, error) {
+		return g.render(ctx, gv)
+	})
+}
+
+// render renders the listers of a group version. It is called concurrently for the
+// group versions.
+func (g *Generator) render(ctx *genall.GenerationContext, gv types.GroupVersions) (*util.RenderedGroupVersion, error) {
+	// Each types.GroupVersions will have only one version.
+	version := string(gv.Versions[0].Version)
+	group, err := internal.NewGroup(gv)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API package is loaded from its directory, which is not necessarily
+	// <inputDir>/<group>/<version> when mapped with --input.
+	pkgs, err := loader.LoadRootsWithConfig(&packages.Config{Dir: gv.Versions[0].Package}, ".")
+	if err != nil {
+		return nil, err
+	}
+	rendered := &util.RenderedGroupVersion{Roots: pkgs}
+
+	for _, root := range pkgs {
+		root.NeedTypesInfo()
+		path := root.PkgPath
+
+		byType := make(map[string][]byte)
+		if eachTypeErr := markers.EachType(ctx.Collector, root, func(info *markers.TypeInfo) {
+			if info.Markers.Get(ruleDefinition.Name) == nil {
+				return
+			}
+			// Like lister-gen, skip the types whose clients cannot list and get.
+			verbs, err := util.ClientVerbs(info)
+			if err != nil {
+				root.AddError(err)
+				return
+			}
+			if !verbs["list"] || !verbs["get"] {
+				return
+			}
+
+			var outContent bytes.Buffer
+			l, err := internal.NewLister(root, info, version, group, info.Markers.Get(nonNamespacedMarker.Name) == nil, &outContent)
+			if err != nil {
+				root.AddError(err)
+				return
+			}
+			if err := l.WriteContent(); err != nil {
+				root.AddError(err)
+				return
+			}
+			byType[info.Name] = outContent.Bytes()
+		}); eachTypeErr != nil {
+			return nil, eachTypeErr
+		}
+
+		if len(byType) == 0 {
+			continue
+		}
+
+		var out bytes.Buffer
+		out.WriteString(g.headerText)
+		if err := internal.NewListerPackage(path, g.listersAPIPath, version, group, &out).WriteContent(); err != nil {
+			return nil, err
+		}
+
+		sortedNames := make([]string, 0, len(byType))
+		for name := range byType {
+			sortedNames = append(sortedNames, name)
+		}
+		sort.Strings(sortedNames)
+		for _, name := range sortedNames {
+			out.Write(byType[name])
+		}
+
+		outBytes, err := format.Source(out.Bytes())
+		if err != nil {
+			root.AddError(err)
+			return nil, err
+		}
+
+		rendered.Files = append(rendered.Files, util.GeneratedFile{
+			Dir:      filepath.Join(g.outputDir, listersPackageName, group.PackageName, version),
+			Filename: group.PackageName + version + extensionGo,
+			Content:  outBytes,
+		})
+	}
+	return rendered, nil
+}
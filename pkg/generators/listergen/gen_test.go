@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listergen
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/kcp-dev/code-generator/pkg/flag"
+)
+
+var _ = Describe("Test generator funcs", func() {
+	Describe("Test validate flags", func() {
+		var (
+			f flag.Flags
+		)
+		BeforeEach(func() {
+			f = flag.Flags{}
+			f.InputDir = "test"
+			f.ListersAPIPath = "examples/"
+			f.GroupVersions = []string{"apps:v1"}
+		})
+
+		It("Should not error when input in set right", func() {
+			Expect(validateFlags(f)).NotTo(HaveOccurred())
+		})
+
+		It("verify input path error", func() {
+			f.InputDir = ""
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("input path to API definition is required."))
+		})
+
+		It("verify listers API path", func() {
+			f.ListersAPIPath = ""
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("specifying listers API path is required to generate listers."))
+		})
+
+		It("verify group version list", func() {
+			f.GroupVersions = []string{}
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("list of group versions for which the listers are to be generated is required."))
+		})
+
+		It("should not require group versions when they are discovered", func() {
+			f.GroupVersions = []string{}
+			f.DiscoverGroupVersions = true
+			Expect(validateFlags(f)).NotTo(HaveOccurred())
+		})
+
+		It("should not require group versions when they are mapped to packages", func() {
+			f.GroupVersions = []string{}
+			f.Inputs = []string{"apps/v1=./apps"}
+			Expect(validateFlags(f)).NotTo(HaveOccurred())
+		})
+
+		It("verify group versions are either listed or discovered", func() {
+			f.DiscoverGroupVersions = true
+			err := validateFlags(f)
+			Expect(err.Error()).To(ContainSubstring("group versions cannot be both listed and discovered."))
+		})
+	})
+})
+
+func TestMetadata(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Test lister generator suite")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	gentype "k8s.io/code-generator/cmd/client-gen/types"
+
+	"github.com/kcp-dev/code-generator/pkg/util"
+)
+
+// Group holds the names given to a group by client-gen, lister-gen and informer-gen,
+// so that the wrappers line up with the code they generated.
+type Group struct {
+	// PackageName is the name of the directories of the group packages, such as
+	// typed/<PackageName>/<version> in the clientset.
+	PackageName string
+	// GoName prefixes the Go identifiers of the group, such as the
+	// <GoName><Version>() accessor of the clientset.
+	GoName string
+}
+
+// NewGroup returns the names of the group version. Its packages are found in the
+// directory of the group, and its Go name is set with a +groupGoName marker in doc.go.
+// Else it is the first segment of the group, itself set with a +groupName marker or
+// else by the directory of the group.
+func NewGroup(gv gentype.GroupVersions) (Group, error) {
+	// this shouldn't happen, we would error out in this condition while validating flags.
+	if len(gv.Versions) == 0 {
+		return Group{}, fmt.Errorf("group %s has no version", gv.Group)
+	}
+	dir := gv.Versions[0].Package
+
+	group := gv.Group.NonEmpty()
+	groupName, err := util.DocMarker(dir, "groupName")
+	if err != nil {
+		return Group{}, err
+	}
+	if groupName != "" {
+		group = groupName
+	}
+	goName, err := util.DocMarker(dir, "groupGoName")
+	if err != nil {
+		return Group{}, err
+	}
+	if goName == "" {
+		goName = strings.Split(group, ".")[0]
+	}
+
+	return Group{
+		PackageName: strings.ToLower(gv.PackageName),
+		GoName:      upperFirst(goName),
+	}, nil
+}
+
+// alias is the lower case Go name, which prefixes the import aliases of the group
+// packages.
+func (g Group) alias() string {
+	return strings.ToLower(g.GoName)
+}
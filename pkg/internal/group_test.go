@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/code-generator/cmd/client-gen/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test group names", func() {
+	var dir string
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "group")
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	groupVersions := func(doc string) types.GroupVersions {
+		pkgDir := filepath.Join(dir, "apps", "v1")
+		Expect(os.MkdirAll(pkgDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(pkgDir, "doc.go"), []byte(doc), 0644)).To(Succeed())
+		return types.GroupVersions{
+			PackageName: "apps",
+			Group:       types.Group("apps"),
+			Versions:    []types.PackageVersion{{Version: "v1", Package: pkgDir}},
+		}
+	}
+
+	It("should name the group after its directory", func() {
+		group, err := NewGroup(groupVersions("package v1\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(group).To(Equal(Group{PackageName: "apps", GoName: "Apps"}))
+	})
+
+	It("should name the group after the first segment of +groupName", func() {
+		group, err := NewGroup(groupVersions("// +groupName=extensions.example.dev\npackage v1\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(group).To(Equal(Group{PackageName: "apps", GoName: "Extensions"}))
+	})
+
+	It("should name the group after +groupGoName", func() {
+		group, err := NewGroup(groupVersions("// +groupName=apps.example.dev\n// +groupGoName=AppsExample\npackage v1\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(group).To(Equal(Group{PackageName: "apps", GoName: "AppsExample"}))
+	})
+})
+
+func TestInternal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Test internal suite")
+}
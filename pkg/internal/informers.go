@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"text/template"
+
+	gentype "k8s.io/code-generator/cmd/client-gen/types"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// informerFactory is used to scaffold the shared informer factory and
+// the interfaces it shares with the group version informers.
+type informerFactory struct {
+	// ClientsetName is the name of the package of the wrapped clientset.
+	ClientsetName string
+	// ClientsetPkgPath is the import path of the wrapped clientset.
+	ClientsetPkgPath string
+	// InformersPkgPath is the import path where the informers are written.
+	InformersPkgPath string
+	// APIs contains one entry per group version.
+	APIs []api
+	// writer wherein outputs are written
+	writer io.Writer
+}
+
+// informerPackage stores the info used to scaffold the content shared by
+// all the informers of a group version.
+type informerPackage struct {
+	Name             string
+	Version          string
+	APIPath          string
+	ListersPkgPath   string
+	ClientsetPkgPath string
+	InformersPkgPath string
+	// Types are the names of the types for which informers are generated.
+	Types  []string
+	writer io.Writer
+}
+
+// informer contains info about each type for which a cluster-aware
+// informer is generated.
+type informer struct {
+	Name          string
+	Version       string
+	PkgName       string
+	IsNamespaced  bool
+	ClientsetName string
+	writer        io.Writer
+
+	NameLowerFirst    string
+	PkgNameUpperFirst string
+	VersionUpperFirst string
+}
+
+// NewInformerFactory returns an informerFactory which can fill the templates to write the
+// shared informer factory and its internal interfaces.
+func NewInformerFactory(clientsetName, clientsetPkgPath, informersPkgPath string, gvs []gentype.GroupVersions, w io.Writer) (*informerFactory, error) {
+	apis, err := groupVersionsToApis(gvs)
+	if err != nil {
+		return nil, err
+	}
+	return &informerFactory{
+		ClientsetName:    clientsetName,
+		ClientsetPkgPath: clientsetPkgPath,
+		InformersPkgPath: informersPkgPath,
+		APIs:             apis,
+		writer:           w,
+	}, nil
+}
+
+func (f *informerFactory) WriteContent() error {
+	templ, err := template.New("factory").Parse(informerFactoryTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(f.writer, f)
+}
+
+// WriteInternalInterfacesContent writes the interfaces shared by the factory and the
+// group version informers, which live in their own package to avoid an import cycle.
+func (f *informerFactory) WriteInternalInterfacesContent() error {
+	templ, err := template.New("internalinterfaces").Parse(informerInternalInterfacesTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(f.writer, f)
+}
+
+// NewInformerPackage returns a new informerPackage instance which is used to write the
+// content shared by the informers of a group version.
+func NewInformerPackage(apiPath, listersPkgPath, clientsetPkgPath, informersPkgPath, version string, group Group, typeNames []string, w io.Writer) *informerPackage {
+	return &informerPackage{
+		Name:             group.alias(),
+		Version:          version,
+		APIPath:          apiPath,
+		ListersPkgPath:   listersPkgPath,
+		ClientsetPkgPath: clientsetPkgPath,
+		InformersPkgPath: informersPkgPath,
+		Types:            typeNames,
+		writer:           w,
+	}
+}
+
+func (p *informerPackage) WriteContent() error {
+	templ, err := template.New("informers").Parse(informersCommonTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(p.writer, p)
+}
+
+// NewInformer returns an informer which can fill the templates to write a cluster-aware
+// informer for the given type.
+func NewInformer(root *loader.Package, info *markers.TypeInfo, clientsetName, version string, group Group, isNamespaced bool, w io.Writer) (*informer, error) {
+	typeInfo := root.TypesInfo.TypeOf(info.RawSpec.Name)
+	if typeInfo == types.Typ[types.Invalid] {
+		return nil, fmt.Errorf("unknown type: %s", info.Name)
+	}
+
+	name := info.RawSpec.Name.Name
+	return &informer{
+		Name:              name,
+		Version:           version,
+		PkgName:           group.alias(),
+		IsNamespaced:      isNamespaced,
+		ClientsetName:     clientsetName,
+		writer:            w,
+		NameLowerFirst:    lowerFirst(name),
+		PkgNameUpperFirst: group.GoName,
+		VersionUpperFirst: upperFirst(version),
+	}, nil
+}
+
+func (i *informer) WriteContent() error {
+	templ, err := template.New("informer").Parse(informerTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(i.writer, i)
+}
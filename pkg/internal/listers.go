@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// listerPackage stores the info used to scaffold the content shared by
+// all the cluster-aware listers of a group version.
+type listerPackage struct {
+	// Name prefixes the import aliases of the group packages.
+	Name string
+	// GroupPackageName is the directory of the group packages.
+	GroupPackageName string
+	APIPath          string
+	ListersPath      string
+	Version          string
+	writer           io.Writer
+}
+
+// lister contains info about each type for which a cluster-aware
+// lister is generated.
+type lister struct {
+	Name         string
+	Version      string
+	PkgName      string
+	IsNamespaced bool
+	writer       io.Writer
+
+	NameLowerFirst string
+	// Resource is the name passed to <Resource>() of the API package
+	// when building NotFound errors, the same way upstream listers do.
+	Resource string
+}
+
+// NewListerPackage returns a new listerPackage instance which is used to write the
+// content shared by the cluster-aware listers of a group version.
+func NewListerPackage(apiPath, listersPath, version string, group Group, w io.Writer) *listerPackage {
+	return &listerPackage{
+		Name:             group.alias(),
+		GroupPackageName: group.PackageName,
+		APIPath:          apiPath,
+		ListersPath:      listersPath,
+		Version:          version,
+		writer:           w,
+	}
+}
+
+func (p *listerPackage) WriteContent() error {
+	templ, err := template.New("listers").Parse(listersCommonTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(p.writer, p)
+}
+
+// NewLister returns a lister which can fill the templates to write a cluster-aware
+// lister for the given type.
+func NewLister(root *loader.Package, info *markers.TypeInfo, version string, group Group, isNamespaced bool, w io.Writer) (*lister, error) {
+	typeInfo := root.TypesInfo.TypeOf(info.RawSpec.Name)
+	if typeInfo == types.Typ[types.Invalid] {
+		return nil, fmt.Errorf("unknown type: %s", info.Name)
+	}
+
+	name := info.RawSpec.Name.Name
+	return &lister{
+		Name:           name,
+		Version:        version,
+		PkgName:        group.alias(),
+		IsNamespaced:   isNamespaced,
+		writer:         w,
+		NameLowerFirst: lowerFirst(name),
+		Resource:       strings.ToLower(name),
+	}, nil
+}
+
+func (l *lister) WriteContent() error {
+	templ, err := template.New("lister").Parse(listerTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(l.writer, l)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Method is a method of the delegate client, as declared by its <Type>Interface, which
+// the wrapper passes through.
+type Method struct {
+	Name string
+	// Params are the parameters of the method, the first one being named ctx when
+	// it is a context.
+	Params string
+	// Args are the arguments passed on to the delegate.
+	Args string
+	// Results are the results of the method, as declared by the interfaces.
+	Results string
+	// ZeroResults are the zero values of the results before the last error, each
+	// followed by a comma, returned along with a cluster mismatch.
+	ZeroResults string
+	// HasContext is true when the first parameter is a context.Context, into which
+	// the cluster is injected.
+	HasContext bool
+	// ReturnsError is true when the last result is an error, through which a
+	// cluster mismatch is returned.
+	ReturnsError bool
+}
+
+// Imports are the packages imported by the typed clients of a group version for the
+// types of their methods, keyed by alias.
+type Imports struct {
+	ByAlias map[string]string
+	// reserved are the packages imported by the typed clients whatever their
+	// methods, keyed by alias.
+	reserved map[string]string
+	// used are the paths of the reserved packages used by the methods.
+	used map[string]bool
+}
+
+// NewImports returns the Imports of the typed clients of a group version, whose API
+// types are found in apiPath, delegate clients in clientPath, apply configurations
+// in applyPath and the implementation shared with the discovery in internalPath.
+func NewImports(group Group, version, apiPath, clientPath, applyPath, internalPath string) *Imports {
+	name := group.alias()
+	reserved := map[string]string{
+		"context":                "context",
+		"fmt":                    "fmt",
+		"kcp":                    "github.com/kcp-dev/apimachinery/pkg/client",
+		"metav1":                 "k8s.io/apimachinery/pkg/apis/meta/v1",
+		"types":                  "k8s.io/apimachinery/pkg/types",
+		"rest":                   "k8s.io/client-go/rest",
+		"logicalcluster":         "github.com/kcp-dev/logicalcluster",
+		"watch":                  "k8s.io/apimachinery/pkg/watch",
+		"internal":               internalPath,
+		name + "api" + version:   apiPath,
+		name + version:           clientPath,
+		name + "apply" + version: applyPath,
+	}
+	return &Imports{
+		ByAlias:  map[string]string{},
+		reserved: reserved,
+		used:     map[string]bool{},
+	}
+}
+
+// Reserved returns the packages imported by the typed clients whatever their
+// methods, keyed by alias.
+func (i *Imports) Reserved() map[string]string {
+	return i.reserved
+}
+
+// Uses returns true when one of the methods uses the reserved package. It is used
+// to import the packages which not every typed client needs.
+func (i *Imports) Uses(path string) bool {
+	return i.used[path]
+}
+
+// Alias returns the alias under which the package is imported, adding it to the
+// imports if needed. The alias is made of the last two elements of the path, ex:
+// autoscalingv1 for k8s.io/api/autoscaling/v1.
+func (i *Imports) Alias(path string) string {
+	for alias, p := range i.reserved {
+		if p == path {
+			i.used[path] = true
+			return alias
+		}
+	}
+	for alias, p := range i.ByAlias {
+		if p == path {
+			return alias
+		}
+	}
+
+	elems := strings.Split(path, "/")
+	if len(elems) > 2 {
+		elems = elems[len(elems)-2:]
+	}
+	base := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(strings.Join(elems, "")))
+
+	alias := base
+	for n := 2; ; n++ {
+		_, reserved := i.reserved[alias]
+		_, used := i.ByAlias[alias]
+		if !reserved && !used {
+			break
+		}
+		alias = fmt.Sprintf("%s%d", base, n)
+	}
+	i.ByAlias[alias] = path
+	return alias
+}
@@ -49,13 +49,21 @@ type interfaceWrapper struct {
 
 // api contains info about each type
 type api struct {
-	Name         string
-	Version      string
-	PkgName      string
-	writer       io.Writer
-	IsNamespaced bool
-	HasStatus    bool
+	Name    string
+	Version string
+	// PkgName prefixes the import aliases of the group packages.
+	PkgName string
+	// GroupPackageName is the directory of the group packages.
+	GroupPackageName string
+	writer           io.Writer
+	IsNamespaced     bool
+	// Methods are the methods of the delegate client which are wrapped.
+	Methods []*Method
+	// ClusterMethods are the List and Watch methods, which are also offered
+	// across all logical clusters.
+	ClusterMethods []*Method
 
+	// PkgNameUpperFirst is the Go name of the group.
 	PkgNameUpperFirst string
 	VersionUpperFirst string
 	NameLowerFirst    string
@@ -63,18 +71,36 @@ type api struct {
 
 // packages stores the info used to scaffold wrapped interfaces content
 type packages struct {
-	Name              string
-	APIPath           string
-	ClientPath        string
+	// Name prefixes the import aliases of the group packages.
+	Name string
+	// GroupPackageName is the directory of the group packages.
+	GroupPackageName string
+	APIPath          string
+	ClientPath       string
+	// NameUpperFirst is the Go name of the group.
 	NameUpperFirst    string
 	VersionUpperFirst string
 	Version           string
 	writer            io.Writer
+
+	// ApplyConfigurationsPath is the import path of the apply configurations
+	// of this group version.
+	ApplyConfigurationsPath string
+	// Imports are the packages imported for the types of the methods.
+	Imports *Imports
+	// Types are the names of the types for which clients are wrapped, sorted.
+	Types []string
+	// InternalPath is the import path of the implementation shared by the typed
+	// clients and the discovery of the clientset.
+	InternalPath string
 }
 
 // NewInterfaceWrapper returns a interfaceWrapper which can fill the templates to wrtie clientset wrappers.
 func NewInterfaceWrapper(clientSetAPIPath, clientsetName, pkgPath string, gvs []gentype.GroupVersions, w io.Writer) (*interfaceWrapper, error) {
-	apis := groupVersionsToApis(gvs)
+	apis, err := groupVersionsToApis(gvs)
+	if err != nil {
+		return nil, err
+	}
 	return &interfaceWrapper{
 		InterfaceName:    filepath.Base(clientSetAPIPath),
 		ClientsetName:    clientsetName,
@@ -94,39 +120,78 @@ func (w *interfaceWrapper) WriteContent() error {
 	return templ.Execute(*w.writer, w)
 }
 
+// WriteFakeContent writes the fake cluster clientset, which keeps one object
+// tracker per logical cluster.
+func (w *interfaceWrapper) WriteFakeContent() error {
+	templ, err := template.New("fake").Parse(fakeClientsetTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(*w.writer, w)
+}
+
+// WriteDiscoveryContent writes the discovery clients scoped to a logical cluster, and
+// their cache.
+func (w *interfaceWrapper) WriteDiscoveryContent() error {
+	templ, err := template.New("discovery").Parse(discoveryTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(*w.writer, w)
+}
+
+// WriteInternalContent writes the implementation shared by the discovery and the typed
+// clients, which is imported by both.
+func (w *interfaceWrapper) WriteInternalContent() error {
+	templ, err := template.New("internal").Parse(internalTempl)
+	if err != nil {
+		return err
+	}
+	return templ.Execute(*w.writer, w)
+}
+
 // groupVersionToApis converts a list of types.GroupVersions to api type which can then be used for
 // templating.
 // Note: `Versions` in type.GroupVersions is assumed to contain only one version for now.
-func groupVersionsToApis(gvs []gentype.GroupVersions) []api {
+func groupVersionsToApis(gvs []gentype.GroupVersions) ([]api, error) {
 	result := make([]api, 0)
 
+	// the accessors of the group versions are named after their Go name, which
+	// must then be unique.
+	seen := map[string]gentype.Group{}
 	for _, gv := range gvs {
 		// this shouldn't happen, we would error out in this condition while validating flags.
 		if len(gv.Versions) <= 0 {
 			continue
 		}
+		group, err := NewGroup(gv)
+		if err != nil {
+			return nil, err
+		}
 		a := &api{
 			Name:    gv.Group.String(),
 			Version: string(gv.Versions[0].Version),
-			PkgName: gv.PackageName,
 		}
-		a.setCased()
+		a.setGroup(group)
+		accessor := a.PkgNameUpperFirst + a.VersionUpperFirst
+		if other, ok := seen[accessor]; ok {
+			return nil, fmt.Errorf("groups %s and %s are both named %s, set a +groupGoName marker in doc.go to tell them apart", other, gv.Group, accessor)
+		}
+		seen[accessor] = gv.Group
 		result = append(result, *a)
 	}
-	return result
+	return result, nil
 }
 
-func (a *api) setCased() {
-	a.PkgNameUpperFirst = upperFirst(a.PkgName)
+// setGroup sets the names of the group of the api, along with the cased names.
+func (a *api) setGroup(group Group) {
+	a.PkgName = group.alias()
+	a.GroupPackageName = group.PackageName
+	a.PkgNameUpperFirst = group.GoName
 	a.VersionUpperFirst = upperFirst(a.Version)
 	a.NameLowerFirst = lowerFirst(a.Name)
 }
 
-func (p *packages) setCased() {
-	p.NameUpperFirst = upperFirst(p.Name)
-	p.VersionUpperFirst = upperFirst(p.Version)
-}
-
 // lowerFirst sets the first alphabet to lowerCase.
 func lowerFirst(s string) string {
 	return strings.ToLower(string(s[0])) + s[1:]
@@ -138,28 +203,18 @@ func upperFirst(s string) string {
 }
 
 // NewPackages returns a new packages instance which is used to write wrapper content.
-func NewPackages(root *loader.Package, apiPath, clientPath, version, group string, w io.Writer) *packages {
-	p := &packages{
-		Name:       sanitize(group),
-		APIPath:    apiPath,
-		Version:    version,
-		ClientPath: clientPath,
-		writer:     w,
+func NewPackages(root *loader.Package, apiPath, clientPath, version string, group Group, w io.Writer) *packages {
+	return &packages{
+		Name:              group.alias(),
+		GroupPackageName:  group.PackageName,
+		APIPath:           apiPath,
+		Version:           version,
+		ClientPath:        clientPath,
+		Imports:           NewImports(group, version, apiPath, clientPath+"/typed/"+group.PackageName+"/"+version, "", ""),
+		NameUpperFirst:    group.GoName,
+		VersionUpperFirst: upperFirst(version),
+		writer:            w,
 	}
-	p.setCased()
-	return p
-}
-
-// group names can have separators in them, ex: "example.com". This is a fix to
-// sanitize those.
-// TODO: Dig into code-gen on how they handle these cases and use the same logic
-// here.
-func sanitize(groupName string) string {
-	if groupName != "" {
-		arr := strings.Split(groupName, ".")
-		groupName = arr[0]
-	}
-	return groupName
 }
 
 func (p *packages) WriteContent() error {
@@ -170,7 +225,7 @@ func (p *packages) WriteContent() error {
 	return templ.Execute(p.writer, p)
 }
 
-func NewAPI(root *loader.Package, info *markers.TypeInfo, version, group string, isNamespaced bool, hasStatus bool, w io.Writer) (*api, error) {
+func NewAPI(root *loader.Package, info *markers.TypeInfo, version string, group Group, isNamespaced bool, methods []*Method, w io.Writer) (*api, error) {
 	typeInfo := root.TypesInfo.TypeOf(info.RawSpec.Name)
 	if typeInfo == types.Typ[types.Invalid] {
 		return nil, fmt.Errorf("unknown type: %s", info.Name)
@@ -179,13 +234,17 @@ func NewAPI(root *loader.Package, info *markers.TypeInfo, version, group string,
 	api := &api{
 		Name:         info.RawSpec.Name.Name,
 		Version:      version,
-		PkgName:      group,
 		writer:       w,
 		IsNamespaced: isNamespaced,
-		HasStatus:    hasStatus,
+		Methods:      methods,
+	}
+	for _, m := range methods {
+		if m.Name == "List" || m.Name == "Watch" {
+			api.ClusterMethods = append(api.ClusterMethods, m)
+		}
 	}
 
-	api.setCased()
+	api.setGroup(group)
 	return api, nil
 }
 
@@ -26,19 +26,24 @@ const wrappedInterfacesTempl = `
 package {{.ClientsetName}}
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	
 	kcp "github.com/kcp-dev/apimachinery/pkg/client"
 	"github.com/kcp-dev/logicalcluster"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	"{{.ClientsetAPIPath}}"
 
+	"{{.TypedPkgPath}}/internal"
+
 	{{$clientPath := .ClientsetAPIPath}}
 	{{$pkg := .TypedPkgPath}}
 	{{ range .APIs }}
-	{{.PkgName}}{{.Version}} "{{$clientPath}}/typed/{{.PkgName}}/{{.Version}}"
-	{{.PkgName}}{{.Version}}client "{{$pkg}}/typed/{{.PkgName}}/{{.Version}}"
+	{{.PkgName}}{{.Version}} "{{$clientPath}}/typed/{{.GroupPackageName}}/{{.Version}}"
+	{{.PkgName}}{{.Version}}client "{{$pkg}}/typed/{{.GroupPackageName}}/{{.Version}}"
 
 	{{ end }}
 )
@@ -47,55 +52,604 @@ import (
 // It uses a custom round tripper that wraps the given client's
 // endpoint. The clientset returned from NewForConfig is kcp
 // cluster-aware.
-func NewForConfig(config *rest.Config) (*ClusterClient, error) {
+func NewForConfig(config *rest.Config, options ...ClusterClientOption) (*ClusterClient, error) {
 	client, err := rest.HTTPClientFor(config)
 	if err != nil {
 		return nil, fmt.Errorf("error creating HTTP client: %w", err)
 	}
+	return NewForConfigAndClient(config, client, options...)
+}
 
-	clusterRoundTripper := kcp.NewClusterRoundTripper(client.Transport)
-	client.Transport = clusterRoundTripper
-
-	delegate, err := {{.InterfaceName}}.NewForConfigAndClient(config, client)
+// NewForConfigAndClient creates a new ClusterClient for the given config and HTTP client.
+// The requests are sent through a copy of the HTTP client, whose transport is wrapped with
+// the custom round tripper unless it already is, so that the same HTTP client, and thus its
+// connections, can be shared by several clientsets. The given HTTP client is not modified.
+func NewForConfigAndClient(config *rest.Config, client *http.Client, options ...ClusterClientOption) (*ClusterClient, error) {
+	delegate, err := {{.InterfaceName}}.NewForConfigAndClient(config, clusterHTTPClient(client))
 	if err != nil {
 		return nil, fmt.Errorf("error creating delegate clientset: %w", err)
 	}
 
-	return &ClusterClient{
+	return newClusterClient(delegate, options), nil
+}
+
+// NewForConfigOrDie creates a new ClusterClient for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(config *rest.Config, options ...ClusterClientOption) *ClusterClient {
+	c, err := NewForConfig(config, options...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// New creates a new ClusterClient for the given RESTClient. If it is a *rest.RESTClient,
+// its requests are sent through a copy of its HTTP client wrapped like in
+// NewForConfigAndClient. Otherwise, it is expected to send the requests to the logical
+// cluster of their context itself.
+func New(c rest.Interface, options ...ClusterClientOption) *ClusterClient {
+	if restClient, ok := c.(*rest.RESTClient); ok && restClient != nil {
+		httpClient := http.DefaultClient
+		if restClient.Client != nil {
+			httpClient = restClient.Client
+		}
+		wrapped := *restClient
+		wrapped.Client = clusterHTTPClient(httpClient)
+		c = &wrapped
+	}
+	return newClusterClient({{.InterfaceName}}.New(c), options)
+}
+
+// newClusterClient creates a ClusterClient wrapping the delegate, with the given options.
+func newClusterClient(delegate {{.InterfaceName}}.Interface, options []ClusterClientOption) *ClusterClient {
+	c := &ClusterClient{
 		delegate: delegate,
-	}, nil
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// ClusterClientOption configures a ClusterClient.
+type ClusterClientOption func(*ClusterClient)
+
+// WithClusterMismatchPolicy sets what the clients do with the requests whose context has a
+// logical cluster different from the one the clients are scoped to.
+func WithClusterMismatchPolicy(policy ClusterMismatchPolicy) ClusterClientOption {
+	return func(c *ClusterClient) {
+		c.clusterMismatchPolicy = policy
+	}
 }
 
+// ClusterMismatchFunc is called for a request whose context has the logical cluster ctxCluster,
+// different from the logical cluster of the client. It returns the context to send the request
+// with, or the error to fail it with. It is taken by the typed clients created with
+// NewWithClusterMismatch and NewClusterWithClusterMismatch.
+type ClusterMismatchFunc = internal.ClusterMismatchFunc
+
+// ClusterMismatchPolicy is what the clients do with the requests whose context has a
+// logical cluster different from the one the clients are scoped to.
+type ClusterMismatchPolicy int
+
+const (
+	// ClusterMismatchError fails the requests. It is the default.
+	ClusterMismatchError ClusterMismatchPolicy = iota
+	// ClusterMismatchPreferContext sends the requests to the logical cluster of their context.
+	ClusterMismatchPreferContext
+	// ClusterMismatchPreferClient sends the requests to the logical cluster of the client.
+	ClusterMismatchPreferClient
+	// ClusterMismatchLogAndContinue logs the mismatch, and sends the requests to the logical
+	// cluster of their context.
+	ClusterMismatchLogAndContinue
+)
+
+// onMismatch returns the context to send a request with, whose context has the logical
+// cluster ctxCluster instead of the cluster of the client, according to the policy.
+func (p ClusterMismatchPolicy) onMismatch(ctx context.Context, ctxCluster, cluster logicalcluster.Name) (context.Context, error) {
+	switch p {
+	case ClusterMismatchPreferContext:
+		return ctx, nil
+	case ClusterMismatchPreferClient:
+		return kcp.WithCluster(ctx, cluster), nil
+	case ClusterMismatchLogAndContinue:
+		klog.Warningf("cluster mismatch: context=%q, client=%q, sending the request to the cluster of the context", ctxCluster, cluster)
+		return ctx, nil
+	default:
+		return ctx, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, cluster)
+	}
+}
+
+// clusterHTTPClient returns a copy of the HTTP client whose transport is wrapped with
+// kcp.ClusterRoundTripper, unless it already is.
+func clusterHTTPClient(client *http.Client) *http.Client {
+	result := *client
+	switch result.Transport.(type) {
+	case *kcp.ClusterRoundTripper:
+	case nil:
+		result.Transport = kcp.NewClusterRoundTripper(http.DefaultTransport)
+	default:
+		result.Transport = kcp.NewClusterRoundTripper(result.Transport)
+	}
+	return &result
+}
+
+// ClusterInterface scopes a clientset to a particular logical cluster, and offers
+// the clients of the group versions which can scope down to a logical cluster.
+// It is implemented by ClusterClient and by the fake ClusterClientset.
+type ClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) {{.InterfaceName}}.Interface
+	{{- range .APIs }}
+	{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}() {{.PkgName}}{{.Version}}client.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}ClusterInterface
+	{{- end }}
+}
+
+var _ ClusterInterface = &ClusterClient{}
+
 // ClusterClient wraps the underlying interface.
 type ClusterClient struct {
-	delegate {{.InterfaceName}}.Interface
+	delegate              {{.InterfaceName}}.Interface
+	clusterMismatchPolicy ClusterMismatchPolicy
 }
 
 // Cluster returns a wrapped interface scoped to a particular cluster.
 func (c *ClusterClient) Cluster(cluster logicalcluster.Name) {{.InterfaceName}}.Interface {
 	return &wrappedInterface{
-		cluster:  cluster,
-		delegate: c.delegate,
+		cluster:               cluster,
+		delegate:              c.delegate,
+		clusterMismatchPolicy: c.clusterMismatchPolicy,
 	}
 }
 
 type wrappedInterface struct {
-	cluster  logicalcluster.Name
-	delegate {{.InterfaceName}}.Interface
+	cluster               logicalcluster.Name
+	delegate              {{.InterfaceName}}.Interface
+	clusterMismatchPolicy ClusterMismatchPolicy
 }
 
-// Discovery retrieves the DiscoveryClient.
+// Discovery retrieves the DiscoveryClient, which sends its requests to the logical cluster.
 func (w *wrappedInterface) Discovery() discovery.DiscoveryInterface {
-	return w.delegate.Discovery()
+	return newDiscovery(w.cluster, w.delegate.Discovery(), w.clusterMismatchPolicy.onMismatch)
 }
 
 {{ range .APIs }}
+// {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} retrieves a client listing and watching {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} resources
+// across all logical clusters.
+func (c *ClusterClient) {{.PkgNameUpperFirst}}{{.VersionUpperFirst}}() {{.PkgName}}{{.Version}}client.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}ClusterInterface {
+	return {{.PkgName}}{{.Version}}client.NewClusterWithClusterMismatch(c.delegate.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}(), c.clusterMismatchPolicy.onMismatch)
+}
+
 // {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} retrieves the {{.PkgNameUpperFirst}}{{.VersionUpperFirst}}Client.
 func (w *wrappedInterface) {{.PkgNameUpperFirst}}{{.VersionUpperFirst}}() {{.PkgName}}{{.Version}}.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}Interface {
-	return {{.PkgName}}{{.Version}}client.New(w.cluster, w.delegate.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}())
+	return {{.PkgName}}{{.Version}}client.NewWithClusterMismatch(w.cluster, w.delegate.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}(), w.clusterMismatchPolicy.onMismatch)
+}
+{{ end }}
+
+`
+
+const fakeClientsetTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+package fake
+
+import (
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/testing"
+
+	"{{.ClientsetAPIPath}}"
+	{{.InterfaceName}}fake "{{.ClientsetAPIPath}}/fake"
+	"{{.TypedPkgPath}}"
+
+	{{$clientPath := .ClientsetAPIPath}}
+	{{$pkg := .TypedPkgPath}}
+	{{ range .APIs }}
+	{{.PkgName}}{{.Version}} "{{$clientPath}}/typed/{{.GroupPackageName}}/{{.Version}}"
+	{{.PkgName}}{{.Version}}client "{{$pkg}}/typed/{{.GroupPackageName}}/{{.Version}}"
+	{{ end }}
+)
+
+var _ {{.ClientsetName}}.ClusterInterface = &ClusterClientset{}
+
+// ClusterClientset implements {{.ClientsetName}}.ClusterInterface. It keeps a separate
+// fake clientset, and thus a separate object tracker, per logical cluster, so that
+// objects written to one logical cluster are not visible from another one.
+//
+// The wildcard cluster lists and watches the objects of every logical cluster, with
+// their logical cluster set, so that it can back the cluster-aware informers. Its
+// other requests are served by a tracker of its own.
+type ClusterClientset struct {
+	lock       sync.Mutex
+	clientsets map[logicalcluster.Name]*{{.InterfaceName}}fake.Clientset
+	wildcard   *{{.InterfaceName}}fake.Clientset
+	// watchers are the watches of the wildcard cluster, which also watch the
+	// logical clusters used once they started.
+	watchers []*clusterWatcher
+}
+
+// NewSimpleClientset returns a ClusterClientset that will respond with the provided objects.
+// Each object is added to the tracker of the logical cluster it belongs to, as returned by
+// logicalcluster.From. Like its non cluster-aware counterpart, it's backed by a very simple
+// object tracker that processes creates, updates and deletions as-is, without applying any
+// validations and/or defaults.
+func NewSimpleClientset(objects ...runtime.Object) *ClusterClientset {
+	byCluster := map[logicalcluster.Name][]runtime.Object{}
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		cluster := logicalcluster.From(accessor)
+		byCluster[cluster] = append(byCluster[cluster], obj)
+	}
+
+	c := &ClusterClientset{
+		clientsets: make(map[logicalcluster.Name]*{{.InterfaceName}}fake.Clientset, len(byCluster)),
+		wildcard:   {{.InterfaceName}}fake.NewSimpleClientset(),
+	}
+	for cluster, objs := range byCluster {
+		c.clientsets[cluster] = {{.InterfaceName}}fake.NewSimpleClientset(objs...)
+	}
+	c.wildcard.PrependReactor("list", "*", c.listAll)
+	c.wildcard.PrependWatchReactor("*", c.watchAll)
+	return c
+}
+
+// clientset returns the fake clientset of the given logical cluster, creating
+// an empty one the first time the logical cluster is used.
+func (c *ClusterClientset) clientset(cluster logicalcluster.Name) *{{.InterfaceName}}fake.Clientset {
+	if cluster == logicalcluster.Wildcard {
+		return c.wildcard
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	clientset, ok := c.clientsets[cluster]
+	if !ok {
+		clientset = {{.InterfaceName}}fake.NewSimpleClientset()
+		c.clientsets[cluster] = clientset
+		watchers := c.watchers[:0]
+		for _, w := range c.watchers {
+			if w.add(cluster, clientset.Tracker()) {
+				watchers = append(watchers, w)
+			}
+		}
+		c.watchers = watchers
+	}
+	return clientset
+}
+
+// Cluster returns the fake clientset scoped to the given logical cluster.
+func (c *ClusterClientset) Cluster(cluster logicalcluster.Name) {{.InterfaceName}}.Interface {
+	return c.clientset(cluster)
+}
+
+// Tracker returns the object tracker of the given logical cluster.
+func (c *ClusterClientset) Tracker(cluster logicalcluster.Name) testing.ObjectTracker {
+	return c.clientset(cluster).Tracker()
+}
+
+// Actions returns the actions performed against the given logical cluster.
+func (c *ClusterClientset) Actions(cluster logicalcluster.Name) []testing.Action {
+	return c.clientset(cluster).Actions()
+}
+
+{{ range .APIs }}
+// {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} retrieves a client of the {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} group version, which scopes
+// down to the fake clientset of a logical cluster, and lists and watches across all
+// logical clusters through the wildcard cluster.
+func (c *ClusterClientset) {{.PkgNameUpperFirst}}{{.VersionUpperFirst}}() {{.PkgName}}{{.Version}}client.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}ClusterInterface {
+	return {{.PkgName}}{{.Version}}client.NewClusterForClients(func(cluster logicalcluster.Name) {{.PkgName}}{{.Version}}.{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}Interface {
+		return c.clientset(cluster).{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}()
+	})
 }
 {{ end }}
+// listAll serves the list requests of the wildcard cluster with the objects of every
+// logical cluster.
+func (c *ClusterClientset) listAll(action testing.Action) (bool, runtime.Object, error) {
+	list, ok := action.(testing.ListActionImpl)
+	if !ok {
+		return false, nil, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result, err := c.wildcard.Tracker().List(list.GetResource(), list.GetKind(), list.GetNamespace())
+	if err != nil {
+		return true, nil, err
+	}
+	var items []runtime.Object
+	for cluster, clientset := range c.clientsets {
+		objs, err := clientset.Tracker().List(list.GetResource(), list.GetKind(), list.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		clusterItems, err := meta.ExtractList(objs)
+		if err != nil {
+			return true, nil, err
+		}
+		for _, item := range clusterItems {
+			item, err := withCluster(cluster, item)
+			if err != nil {
+				return true, nil, err
+			}
+			items = append(items, item)
+		}
+	}
+	return true, result, meta.SetList(result, items)
+}
+
+// watchAll serves the watch requests of the wildcard cluster with the events of every
+// logical cluster.
+func (c *ClusterClientset) watchAll(action testing.Action) (bool, watch.Interface, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	w := &clusterWatcher{
+		gvr:       action.GetResource(),
+		namespace: action.GetNamespace(),
+		result:    make(chan watch.Event),
+		stopCh:    make(chan struct{}),
+	}
+	for cluster, clientset := range c.clientsets {
+		if !w.add(cluster, clientset.Tracker()) {
+			break
+		}
+	}
+	if w.err != nil {
+		w.Stop()
+		return true, nil, w.err
+	}
+	c.watchers = append(c.watchers, w)
+	return true, w, nil
+}
+
+// clusterWatcher merges the watches of the logical clusters.
+type clusterWatcher struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	result    chan watch.Event
+	stopCh    chan struct{}
+
+	lock    sync.Mutex
+	stopped bool
+	err     error
+	watches []watch.Interface
+}
+
+// add watches the tracker of the logical cluster. It returns false once the watcher
+// is stopped or failed.
+func (w *clusterWatcher) add(cluster logicalcluster.Name, tracker testing.ObjectTracker) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopped || w.err != nil {
+		return false
+	}
+	clusterWatch, err := tracker.Watch(w.gvr, w.namespace)
+	if err != nil {
+		w.err = err
+		return false
+	}
+	w.watches = append(w.watches, clusterWatch)
+
+	go func() {
+		for event := range clusterWatch.ResultChan() {
+			if event.Object != nil {
+				if obj, err := withCluster(cluster, event.Object); err == nil {
+					event.Object = obj
+				}
+			}
+			select {
+			case w.result <- event:
+			case <-w.stopCh:
+				return
+			}
+		}
+	}()
+	return true
+}
+
+// Stop stops the watches of all the logical clusters.
+func (w *clusterWatcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopped {
+		return
+	}
+	w.stopped = true
+	close(w.stopCh)
+	for _, clusterWatch := range w.watches {
+		clusterWatch.Stop()
+	}
+}
+
+// ResultChan returns the events of all the logical clusters.
+func (w *clusterWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// withCluster returns a copy of the object with its logical cluster set.
+func withCluster(cluster logicalcluster.Name, obj runtime.Object) (runtime.Object, error) {
+	obj = obj.DeepCopyObject()
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	accessor.SetClusterName(cluster.String())
+	return obj, nil
+}
+`
+
+const discoveryTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+package {{.ClientsetName}}
+
+import (
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+
+	"{{.TypedPkgPath}}/internal"
+)
+
+// newDiscovery returns a discovery client sending its requests to the given logical cluster.
+// Discovery requests have no context to carry the logical cluster, so it is set by the
+// transport of a copy of the REST client of the delegate. Requests whose context has a
+// different logical cluster are passed to onMismatch, or fail if it is nil.
+func newDiscovery(cluster logicalcluster.Name, delegate discovery.DiscoveryInterface, onMismatch internal.ClusterMismatchFunc) discovery.DiscoveryInterface {
+	restClient, ok := delegate.RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		// such as the discovery client of a fake clientset, which sends no requests.
+		return delegate
+	}
+	return discovery.NewDiscoveryClient(internal.WithCluster(cluster, restClient, onMismatch))
+}
+
+// CachedDiscovery caches the discovery of every logical cluster in memory. Logical
+// clusters expose different APIs, for instance through APIBindings, so that RESTMappers
+// are built per logical cluster, from its own discovery.
+type CachedDiscovery struct {
+	client ClusterInterface
+
+	lock   sync.Mutex
+	caches map[logicalcluster.Name]discovery.CachedDiscoveryInterface
+}
+
+// NewCachedDiscovery returns a CachedDiscovery of the logical clusters of the client.
+func NewCachedDiscovery(client ClusterInterface) *CachedDiscovery {
+	return &CachedDiscovery{
+		client: client,
+		caches: map[logicalcluster.Name]discovery.CachedDiscoveryInterface{},
+	}
+}
 
+// Cluster returns the cached discovery client of the given logical cluster, which is
+// created on first use and then shared.
+func (d *CachedDiscovery) Cluster(cluster logicalcluster.Name) discovery.CachedDiscoveryInterface {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	cache, ok := d.caches[cluster]
+	if !ok {
+		cache = memory.NewMemCacheClient(d.client.Cluster(cluster).Discovery())
+		d.caches[cluster] = cache
+	}
+	return cache
+}
+
+// Invalidate makes the cached discovery client of the given logical cluster fetch the
+// discovery again on next use, such as when its APIBindings changed.
+func (d *CachedDiscovery) Invalidate(cluster logicalcluster.Name) {
+	d.lock.Lock()
+	cache, ok := d.caches[cluster]
+	d.lock.Unlock()
+
+	if ok {
+		cache.Invalidate()
+	}
+}
+
+// Forget drops the cache of the given logical cluster, such as when it is deleted. The
+// cached discovery clients already returned by Cluster keep their cache.
+func (d *CachedDiscovery) Forget(cluster logicalcluster.Name) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	delete(d.caches, cluster)
+}
+`
+
+const internalTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+// Package internal has the implementation shared by the discovery and the typed
+// clients of the {{.ClientsetName}} package.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	kcp "github.com/kcp-dev/apimachinery/pkg/client"
+	"github.com/kcp-dev/logicalcluster"
+	"k8s.io/client-go/rest"
+)
+
+// ClusterMismatchFunc is called for a request whose context has the logical cluster ctxCluster,
+// different from the logical cluster of the client. It returns the context to send the request
+// with, or the error to fail it with.
+type ClusterMismatchFunc func(ctx context.Context, ctxCluster, cluster logicalcluster.Name) (context.Context, error)
+
+// WithCluster returns a copy of the REST client whose requests are sent to the given
+// logical cluster. Requests whose context has a different logical cluster are passed to
+// onMismatch, or fail if it is nil.
+func WithCluster(cluster logicalcluster.Name, client *rest.RESTClient, onMismatch ClusterMismatchFunc) *rest.RESTClient {
+	httpClient := http.DefaultClient
+	if client.Client != nil {
+		httpClient = client.Client
+	}
+	scoped := *httpClient
+	if scoped.Transport == nil {
+		scoped.Transport = http.DefaultTransport
+	}
+	scoped.Transport = &clusterRoundTripper{cluster: cluster, delegate: scoped.Transport, onMismatch: onMismatch}
+
+	result := *client
+	result.Client = &scoped
+	return &result
+}
+
+// clusterRoundTripper sets the logical cluster in the context of the requests, for the
+// kcp.ClusterRoundTripper of the client to send them to it.
+type clusterRoundTripper struct {
+	cluster    logicalcluster.Name
+	delegate   http.RoundTripper
+	onMismatch ClusterMismatchFunc
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *clusterRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctxCluster, ok := kcp.ClusterFromContext(req.Context())
+	if !ok {
+		return rt.delegate.RoundTrip(req.WithContext(kcp.WithCluster(req.Context(), rt.cluster)))
+	} else if ctxCluster != rt.cluster {
+		if rt.onMismatch == nil {
+			return nil, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, rt.cluster)
+		}
+		ctx, err := rt.onMismatch(req.Context(), ctxCluster, rt.cluster)
+		if err != nil {
+			return nil, err
+		}
+		return rt.delegate.RoundTrip(req.WithContext(ctx))
+	}
+	return rt.delegate.RoundTrip(req)
+}
 `
 
 const commonTempl = `
@@ -111,142 +665,666 @@ import (
 	"context"
 	"fmt"
 	{{.Name}}api{{.Version}} "{{.APIPath}}"
-	{{.Name}}{{.Version}} "{{.ClientPath}}/typed/{{.Name}}/{{.Version}}"
+	{{.Name}}{{.Version}} "{{.ClientPath}}/typed/{{.GroupPackageName}}/{{.Version}}"
+	{{- if .Imports.Uses .ApplyConfigurationsPath}}
+	{{.Name}}apply{{.Version}} "{{.ApplyConfigurationsPath}}"
+	{{- end}}
+	{{- range $alias, $path := .Imports.ByAlias}}
+	{{$alias}} "{{$path}}"
+	{{- end}}
 
 	kcp "github.com/kcp-dev/apimachinery/pkg/client"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	{{- if .Imports.Uses "k8s.io/apimachinery/pkg/types"}}
 	"k8s.io/apimachinery/pkg/types"
+	{{- end}}
 	"k8s.io/client-go/rest"
 	"github.com/kcp-dev/logicalcluster"
+	{{- if .Imports.Uses "k8s.io/apimachinery/pkg/watch"}}
 	"k8s.io/apimachinery/pkg/watch"
+	{{- end}}
+
+	"{{.InternalPath}}"
 )
 
 // Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}} wraps the client interface with a
 // logical cluster.
 type Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}} struct {
-	cluster  logicalcluster.Name
-	delegate {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface
+	cluster    logicalcluster.Name
+	delegate   {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface
+	onMismatch internal.ClusterMismatchFunc
 }
 
 // New creates a Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}} with the given logical cluster and client interface.
 func New(cluster logicalcluster.Name, delegate {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface) *Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}}{
-	return &Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}}{cluster: cluster, delegate: delegate}
+	return NewWithClusterMismatch(cluster, delegate, nil)
 }
 
-// RESTClient returns the underlying RESTClient.
+// NewWithClusterMismatch creates a Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}} with the given logical cluster and client interface,
+// which calls onMismatch for the requests whose context has a different logical cluster. They
+// fail if it is nil.
+func NewWithClusterMismatch(cluster logicalcluster.Name, delegate {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface, onMismatch internal.ClusterMismatchFunc) *Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}} {
+	return &Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}}{cluster: cluster, delegate: delegate, onMismatch: onMismatch}
+}
+
+// RESTClient returns a copy of the underlying RESTClient, whose requests are sent to the
+// logical cluster. Requests whose context has a different logical cluster are handled like
+// by the typed clients.
 func (w *Wrapped{{.NameUpperFirst}}{{.VersionUpperFirst}}) RESTClient() rest.Interface {
-	return w.delegate.RESTClient()
+	restClient, ok := w.delegate.RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		// such as the client of a fake clientset, which sends no requests.
+		return w.delegate.RESTClient()
+	}
+	return internal.WithCluster(w.cluster, restClient, w.onMismatch)
+}
+
+// Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} lists and watches the resources of the group version
+// across all logical clusters.
+type Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} struct {
+	clientFor func(cluster logicalcluster.Name) {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface
+}
+
+// NewCluster creates a Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} with the given client interface.
+func NewCluster(delegate {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface) *Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} {
+	return NewClusterWithClusterMismatch(delegate, nil)
+}
+
+// NewClusterWithClusterMismatch creates a Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} with the given client interface, whose
+// clients call onMismatch like the ones created by NewWithClusterMismatch.
+func NewClusterWithClusterMismatch(delegate {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface, onMismatch internal.ClusterMismatchFunc) *Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} {
+	return NewClusterForClients(func(cluster logicalcluster.Name) {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface {
+		return NewWithClusterMismatch(cluster, delegate, onMismatch)
+	})
+}
+
+// NewClusterForClients creates a Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} whose client of a logical cluster is
+// returned by clientFor, such as the client of the fake clientset of the logical cluster.
+func NewClusterForClients(clientFor func(cluster logicalcluster.Name) {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface) *Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}} {
+	return &Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}}{clientFor: clientFor}
+}
+
+// {{.NameUpperFirst}}{{.VersionUpperFirst}}ClusterInterface can scope down to the client of the group version
+// for a single logical cluster, or to the clients of its resources.
+type {{.NameUpperFirst}}{{.VersionUpperFirst}}ClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface
+	{{- range .Types}}
+	{{.}}sClusterGetter
+	{{- end}}
+}
+
+var _ {{.NameUpperFirst}}{{.VersionUpperFirst}}ClusterInterface = &Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}}{}
+
+// Cluster returns the client of the group version scoped to the given logical cluster.
+func (c *Cluster{{.NameUpperFirst}}{{.VersionUpperFirst}}) Cluster(cluster logicalcluster.Name) {{.Name}}{{.Version}}.{{.NameUpperFirst}}{{.VersionUpperFirst}}Interface {
+	return c.clientFor(cluster)
 }
 
 `
 
 const wrapperMethodsTempl = `
-// Wrapped{{.PkgNameUpperFirst}}{{.VersionUpperFirst}} contains the wrapped logical cluster and interface.
+// {{.Name}}s returns a client for the {{.Name}}s of the logical cluster{{if .IsNamespaced}} in the namespace{{end}}.
 func (w *Wrapped{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}) {{.Name}}s{{if .IsNamespaced}}(namespace string){{else}}(){{end}} {{.PkgName}}{{.Version}}.{{.Name}}Interface {
 	return &wrapped{{.Name}}{
-		cluster:  w.cluster,
-		delegate: w.delegate.{{.Name}}s{{if .IsNamespaced}}(namespace){{else}}(){{end}},
+		cluster:    w.cluster,
+		delegate:   w.delegate.{{.Name}}s{{if .IsNamespaced}}(namespace){{else}}(){{end}},
+		onMismatch: w.onMismatch,
 	}
 }
 
 type wrapped{{.Name}} struct {
-	cluster  logicalcluster.Name
-	delegate {{.PkgName}}{{.Version}}.{{.Name}}Interface
+	cluster    logicalcluster.Name
+	delegate   {{.PkgName}}{{.Version}}.{{.Name}}Interface
+	onMismatch internal.ClusterMismatchFunc
 }
 
 // checkCluster retrieves the logical cluster name from the given context and checks
-// if it is the same as the one passed while creating a wrapped{{.Name}}. It errors when
-// there is a mismatch.
+// if it is the same as the one passed while creating a wrapped{{.Name}}. On a mismatch,
+// it returns the result of onMismatch, or an error if it is nil.
 func (w *wrapped{{.Name}}) checkCluster(ctx context.Context) (context.Context, error) {
 	ctxCluster, ok := kcp.ClusterFromContext(ctx)
 	if !ok {
 		return kcp.WithCluster(ctx, w.cluster), nil
 	} else if ctxCluster != w.cluster {
+		if w.onMismatch != nil {
+			return w.onMismatch(ctx, ctxCluster, w.cluster)
+		}
 		return ctx, fmt.Errorf("cluster mismatch: context=%q, client=%q", ctxCluster, w.cluster)
 	}
 	return ctx, nil
 }
 
-// Create implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) Create(ctx context.Context, {{.NameLowerFirst}} *{{.PkgName}}api{{.Version}}.{{.Name}}, opts metav1.CreateOptions) (*{{.PkgName}}api{{.Version}}.{{.Name}}, error) {
+{{range .Methods}}
+// {{.Name}} implements {{$.Name}}Interface.
+func (w *wrapped{{$.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	{{- if and .HasContext .ReturnsError}}
 	ctx, err := w.checkCluster(ctx)
 	if err != nil {
-		return nil, err
+		return {{.ZeroResults}}err
+	}
+	{{- else if .HasContext}}
+	// the method has no error to return a mismatch with, so the context is then
+	// passed through unchanged.
+	if clusterCtx, err := w.checkCluster(ctx); err == nil {
+		ctx = clusterCtx
 	}
-	return w.delegate.Create(ctx, {{.NameLowerFirst}}, opts)
+	{{- end}}
+	{{if .Results}}return {{end}}w.delegate.{{.Name}}({{.Args}})
 }
+{{end}}
 
-// Update implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) Update(ctx context.Context, {{.NameLowerFirst}} *{{.PkgName}}api{{.Version}}.{{.Name}}, opts metav1.UpdateOptions) (*{{.PkgName}}api{{.Version}}.{{.Name}}, error) {
-	ctx, err := w.checkCluster(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return w.delegate.Update(ctx, {{.NameLowerFirst}}, opts)
+// {{.Name}}sClusterGetter has a method to return a {{.Name}}ClusterInterface.
+type {{.Name}}sClusterGetter interface {
+	{{.Name}}s() {{.Name}}ClusterInterface
 }
 
-{{if .HasStatus}}
- // UpdateStatus implements {{.Name}}Interface. It was generated because the type contains a Status member.
- func (w *wrapped{{.Name}}) UpdateStatus(ctx context.Context, {{.NameLowerFirst}} *{{.PkgName}}api{{.Version}}.{{.Name}}, opts metav1.UpdateOptions) (*{{.PkgName}}api{{.Version}}.{{.Name}}, error) {
- 	ctx, err := w.checkCluster(ctx)
- 	if err != nil {
- 		return nil, err
- 	}
- 	return w.delegate.UpdateStatus(ctx, {{.NameLowerFirst}}, opts)
- }
- {{end}}
+// {{.Name}}ClusterInterface can scope down to a single logical cluster{{if .IsNamespaced}} and namespace{{end}}.
+{{- if .ClusterMethods}}
+// It also lists and watches {{.Name}}s across all logical clusters{{if .IsNamespaced}} and namespaces{{end}}, and
+// the logical cluster of an item is found with logicalcluster.From.
+{{- end}}
+type {{.Name}}ClusterInterface interface {
+	Cluster(cluster logicalcluster.Name) {{if .IsNamespaced}}{{.Name}}sNamespacer{{else}}{{.PkgName}}{{.Version}}.{{.Name}}Interface{{end}}
+	{{- range .ClusterMethods}}
+	{{.Name}}({{.Params}}) {{.Results}}
+	{{- end}}
+}
 
-// Update implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
-	ctx, err := w.checkCluster(ctx)
-	if err != nil {
-		return err
-	}
-	return w.delegate.Delete(ctx, name, opts)
+// {{.Name}}s returns a client for {{.Name}}s, which can scope down to a logical cluster.
+func (c *Cluster{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}) {{.Name}}s() {{.Name}}ClusterInterface {
+	return &cluster{{.Name}}{delegate: c}
 }
 
-// DeleteCollection implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listopts metav1.ListOptions) error {
-	ctx, err := w.checkCluster(ctx)
+// cluster{{.Name}} scopes {{.Name}}s down to a logical cluster, and offers the methods
+// of wrapped{{.Name}} which can be used with the wildcard logical cluster.
+type cluster{{.Name}} struct {
+	delegate *Cluster{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}
+}
+
+// Cluster implements {{.Name}}ClusterInterface.
+func (c *cluster{{.Name}}) Cluster(cluster logicalcluster.Name) {{if .IsNamespaced}}{{.Name}}sNamespacer{{else}}{{.PkgName}}{{.Version}}.{{.Name}}Interface{{end}} {
+	{{- if .IsNamespaced}}
+	return &{{.NameLowerFirst}}sNamespacer{cluster: cluster, delegate: c.delegate}
+	{{- else}}
+	return c.delegate.Cluster(cluster).{{.Name}}s()
+	{{- end}}
+}
+{{range .ClusterMethods}}
+// {{.Name}} implements {{$.Name}}ClusterInterface.
+func (c *cluster{{$.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	return c.delegate.Cluster(logicalcluster.Wildcard).{{$.Name}}s({{if $.IsNamespaced}}metav1.NamespaceAll{{end}}).{{.Name}}({{.Args}})
+}
+{{end}}
+{{- if .IsNamespaced}}
+// {{.Name}}sNamespacer can scope down to a single namespace of a logical cluster.
+type {{.Name}}sNamespacer interface {
+	Namespace(namespace string) {{.PkgName}}{{.Version}}.{{.Name}}Interface
+}
+
+type {{.NameLowerFirst}}sNamespacer struct {
+	cluster  logicalcluster.Name
+	delegate *Cluster{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}
+}
+
+// Namespace implements {{.Name}}sNamespacer.
+func (n *{{.NameLowerFirst}}sNamespacer) Namespace(namespace string) {{.PkgName}}{{.Version}}.{{.Name}}Interface {
+	return n.delegate.Cluster(n.cluster).{{.Name}}s(namespace)
+}
+{{- end}}
+`
+
+const listersCommonTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+package {{.Version}}
+
+import (
+	{{.Name}}api{{.Version}} "{{.APIPath}}"
+	{{.Name}}{{.Version}} "{{.ListersPath}}/{{.GroupPackageName}}/{{.Version}}"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// listByIndex calls appendFn for every object stored under indexKey in the given
+// index of the indexer that matches the selector.
+func listByIndex(indexer cache.Indexer, indexName, indexKey string, selector labels.Selector, appendFn cache.AppendFunc) error {
+	selectAll := selector.Empty()
+	list, err := indexer.ByIndex(indexName, indexKey)
 	if err != nil {
 		return err
 	}
-	return w.delegate.DeleteCollection(ctx, opts, listopts)
+	for _, m := range list {
+		if selectAll {
+			appendFn(m)
+			continue
+		}
+		metadata, err := meta.Accessor(m)
+		if err != nil {
+			return err
+		}
+		if selector.Matches(labels.Set(metadata.GetLabels())) {
+			appendFn(m)
+		}
+	}
+	return nil
 }
 
-// Get implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) Get(ctx context.Context, name string, opts metav1.GetOptions) (*{{.PkgName}}api{{.Version}}.{{.Name}}, error) {
-	ctx, err := w.checkCluster(ctx)
+`
+
+const listerTempl = `
+// {{.Name}}ClusterLister can list {{.Name}}s across all logical clusters, or scope down
+// to a {{.PkgName}}{{.Version}}.{{.Name}}Lister for a single logical cluster.
+type {{.Name}}ClusterLister struct {
+	indexer cache.Indexer
+}
+
+// New{{.Name}}ClusterLister returns a new {{.Name}}ClusterLister. The indexer is expected to be
+// keyed by kcpcache.ClusterAwareKeyFunc and to have the kcpcache.ClusterIndexName and
+// kcpcache.ClusterAndNamespaceIndexName indexes.
+func New{{.Name}}ClusterLister(indexer cache.Indexer) *{{.Name}}ClusterLister {
+	return &{{.Name}}ClusterLister{indexer: indexer}
+}
+
+// List lists all {{.Name}}s in the indexer across all logical clusters.
+func (s *{{.Name}}ClusterLister) List(selector labels.Selector) (ret []*{{.PkgName}}api{{.Version}}.{{.Name}}, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*{{.PkgName}}api{{.Version}}.{{.Name}}))
+	})
+	return ret, err
+}
+
+// Cluster returns a lister that can list and get {{.Name}}s in the given logical cluster.
+func (s *{{.Name}}ClusterLister) Cluster(cluster logicalcluster.Name) {{.PkgName}}{{.Version}}.{{.Name}}Lister {
+	return &{{.NameLowerFirst}}Lister{indexer: s.indexer, cluster: cluster}
+}
+
+// {{.NameLowerFirst}}Lister implements {{.PkgName}}{{.Version}}.{{.Name}}Lister for a single logical cluster.
+type {{.NameLowerFirst}}Lister struct {
+	indexer cache.Indexer
+	cluster logicalcluster.Name
+}
+
+// List lists all {{.Name}}s in the logical cluster.
+func (s *{{.NameLowerFirst}}Lister) List(selector labels.Selector) (ret []*{{.PkgName}}api{{.Version}}.{{.Name}}, err error) {
+	err = listByIndex(s.indexer, kcpcache.ClusterIndexName, kcpcache.ToClusterAwareKey(s.cluster.String(), "", ""), selector, func(m interface{}) {
+		ret = append(ret, m.(*{{.PkgName}}api{{.Version}}.{{.Name}}))
+	})
+	return ret, err
+}
+{{if .IsNamespaced}}
+// {{.Name}}s returns a lister that can list and get {{.Name}}s in the given namespace of the logical cluster.
+func (s *{{.NameLowerFirst}}Lister) {{.Name}}s(namespace string) {{.PkgName}}{{.Version}}.{{.Name}}NamespaceLister {
+	return &{{.NameLowerFirst}}NamespaceLister{indexer: s.indexer, cluster: s.cluster, namespace: namespace}
+}
+
+// {{.NameLowerFirst}}NamespaceLister implements {{.PkgName}}{{.Version}}.{{.Name}}NamespaceLister for a single
+// namespace of a logical cluster.
+type {{.NameLowerFirst}}NamespaceLister struct {
+	indexer   cache.Indexer
+	cluster   logicalcluster.Name
+	namespace string
+}
+
+// List lists all {{.Name}}s in the namespace of the logical cluster.
+func (s *{{.NameLowerFirst}}NamespaceLister) List(selector labels.Selector) (ret []*{{.PkgName}}api{{.Version}}.{{.Name}}, err error) {
+	err = listByIndex(s.indexer, kcpcache.ClusterAndNamespaceIndexName, kcpcache.ToClusterAwareKey(s.cluster.String(), s.namespace, ""), selector, func(m interface{}) {
+		ret = append(ret, m.(*{{.PkgName}}api{{.Version}}.{{.Name}}))
+	})
+	return ret, err
+}
+
+// Get retrieves the {{.Name}} with the given name from the namespace of the logical cluster.
+func (s *{{.NameLowerFirst}}NamespaceLister) Get(name string) (*{{.PkgName}}api{{.Version}}.{{.Name}}, error) {
+	obj, exists, err := s.indexer.GetByKey(kcpcache.ToClusterAwareKey(s.cluster.String(), s.namespace, name))
 	if err != nil {
 		return nil, err
 	}
-	return w.delegate.Get(ctx, name, opts)
+	if !exists {
+		return nil, errors.NewNotFound({{.PkgName}}api{{.Version}}.Resource("{{.Resource}}"), name)
+	}
+	return obj.(*{{.PkgName}}api{{.Version}}.{{.Name}}), nil
 }
-
-// List implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) List(ctx context.Context, opts metav1.ListOptions) (*{{.PkgName}}api{{.Version}}.{{.Name}}List, error) {
-	ctx, err := w.checkCluster(ctx)
+{{else}}
+// Get retrieves the {{.Name}} with the given name from the logical cluster.
+func (s *{{.NameLowerFirst}}Lister) Get(name string) (*{{.PkgName}}api{{.Version}}.{{.Name}}, error) {
+	obj, exists, err := s.indexer.GetByKey(kcpcache.ToClusterAwareKey(s.cluster.String(), "", name))
 	if err != nil {
 		return nil, err
 	}
-	return w.delegate.List(ctx, opts)
+	if !exists {
+		return nil, errors.NewNotFound({{.PkgName}}api{{.Version}}.Resource("{{.Resource}}"), name)
+	}
+	return obj.(*{{.PkgName}}api{{.Version}}.{{.Name}}), nil
 }
+{{end}}`
 
-// Watch implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
-	ctx, err := w.checkCluster(ctx)
-	if err != nil {
-		return nil, err
+const informerFactoryTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+package informers
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"{{.ClientsetPkgPath}}"
+	"{{.InformersPkgPath}}/internalinterfaces"
+
+	{{$informersPkg := .InformersPkgPath}}
+	{{ range .APIs }}
+	{{.PkgName}}{{.Version}} "{{$informersPkg}}/{{.GroupPackageName}}/{{.Version}}"
+	{{ end }}
+)
+
+// SharedInformerOption defines the functional option type for SharedInformerFactory.
+type SharedInformerOption func(*sharedInformerFactory) *sharedInformerFactory
+
+type sharedInformerFactory struct {
+	client           {{.ClientsetName}}.ClusterInterface
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	lock             sync.Mutex
+	defaultResync    time.Duration
+	customResync     map[reflect.Type]time.Duration
+
+	informers map[reflect.Type]cache.SharedIndexInformer
+	// startedInformers is used for tracking which informers have been started.
+	// This allows Start() to be called multiple times safely.
+	startedInformers map[reflect.Type]bool
+}
+
+// WithCustomResyncConfig sets a custom resync period for the specified informer types.
+func WithCustomResyncConfig(resyncConfig map[metav1.Object]time.Duration) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		for k, v := range resyncConfig {
+			factory.customResync[reflect.TypeOf(k)] = v
+		}
+		return factory
 	}
-	return w.delegate.Watch(ctx, opts)
 }
 
-// Patch implements {{.Name}}Interface.
-func (w *wrapped{{.Name}}) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *{{.PkgName}}api{{.Version}}.{{.Name}}, err error) {
-	ctx, err = w.checkCluster(ctx)
-	if err != nil {
-		return nil, err
+// WithTweakListOptions sets a custom filter on all listers of the configured SharedInformerFactory.
+func WithTweakListOptions(tweakListOptions internalinterfaces.TweakListOptionsFunc) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		factory.tweakListOptions = tweakListOptions
+		return factory
+	}
+}
+
+// WithNamespace limits the SharedInformerFactory to the specified namespace in every logical cluster.
+func WithNamespace(namespace string) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		factory.namespace = namespace
+		return factory
+	}
+}
+
+// NewSharedInformerFactory constructs a new instance of sharedInformerFactory for all namespaces
+// of all logical clusters.
+func NewSharedInformerFactory(client {{.ClientsetName}}.ClusterInterface, defaultResync time.Duration) SharedInformerFactory {
+	return NewSharedInformerFactoryWithOptions(client, defaultResync)
+}
+
+// NewSharedInformerFactoryWithOptions constructs a new instance of a SharedInformerFactory with additional options.
+func NewSharedInformerFactoryWithOptions(client {{.ClientsetName}}.ClusterInterface, defaultResync time.Duration, options ...SharedInformerOption) SharedInformerFactory {
+	factory := &sharedInformerFactory{
+		client:           client,
+		namespace:        metav1.NamespaceAll,
+		defaultResync:    defaultResync,
+		informers:        make(map[reflect.Type]cache.SharedIndexInformer),
+		startedInformers: make(map[reflect.Type]bool),
+		customResync:     make(map[reflect.Type]time.Duration),
+	}
+
+	// Apply all options
+	for _, opt := range options {
+		factory = opt(factory)
+	}
+
+	return factory
+}
+
+// Start initializes all requested informers.
+func (f *sharedInformerFactory) Start(stopCh <-chan struct{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for informerType, informer := range f.informers {
+		if !f.startedInformers[informerType] {
+			go informer.Run(stopCh)
+			f.startedInformers[informerType] = true
+		}
+	}
+}
+
+// WaitForCacheSync waits for all started informers' cache were synced.
+func (f *sharedInformerFactory) WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool {
+	informers := func() map[reflect.Type]cache.SharedIndexInformer {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		informers := map[reflect.Type]cache.SharedIndexInformer{}
+		for informerType, informer := range f.informers {
+			if f.startedInformers[informerType] {
+				informers[informerType] = informer
+			}
+		}
+		return informers
+	}()
+
+	res := map[reflect.Type]bool{}
+	for informType, informer := range informers {
+		res[informType] = cache.WaitForCacheSync(stopCh, informer.HasSynced)
+	}
+	return res
+}
+
+// InformerFor returns the SharedIndexInformer for obj using an internal
+// client.
+func (f *sharedInformerFactory) InformerFor(obj runtime.Object, newFunc internalinterfaces.NewInformerFunc) cache.SharedIndexInformer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	informerType := reflect.TypeOf(obj)
+	informer, exists := f.informers[informerType]
+	if exists {
+		return informer
 	}
-	return w.delegate.Patch(ctx, name, pt, data, opts, subresources...)
+
+	resyncPeriod, exists := f.customResync[informerType]
+	if !exists {
+		resyncPeriod = f.defaultResync
+	}
+
+	informer = newFunc(f.client, resyncPeriod)
+	f.informers[informerType] = informer
+
+	return informer
+}
+
+// SharedInformerFactory provides shared informers for resources in all known
+// API group versions. The informers list and watch across all logical clusters.
+type SharedInformerFactory interface {
+	internalinterfaces.SharedInformerFactory
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+	{{ range .APIs }}
+	{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}() {{.PkgName}}{{.Version}}.Interface
+	{{- end }}
+}
+
+{{ range .APIs }}
+// {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} retrieves the informers of the {{.PkgNameUpperFirst}}{{.VersionUpperFirst}} group version.
+func (f *sharedInformerFactory) {{.PkgNameUpperFirst}}{{.VersionUpperFirst}}() {{.PkgName}}{{.Version}}.Interface {
+	return {{.PkgName}}{{.Version}}.New(f, f.namespace, f.tweakListOptions)
+}
+{{ end }}
+`
+
+const informerInternalInterfacesTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+package internalinterfaces
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"{{.ClientsetPkgPath}}"
+)
+
+// NewInformerFunc takes a {{.ClientsetName}}.ClusterInterface and a resync period to return a SharedIndexInformer.
+type NewInformerFunc func({{.ClientsetName}}.ClusterInterface, time.Duration) cache.SharedIndexInformer
+
+// SharedInformerFactory a small interface to allow for adding an informer without an import cycle.
+type SharedInformerFactory interface {
+	Start(stopCh <-chan struct{})
+	InformerFor(obj runtime.Object, newFunc NewInformerFunc) cache.SharedIndexInformer
+}
+
+// TweakListOptionsFunc is a function that transforms a metav1.ListOptions.
+type TweakListOptionsFunc func(*metav1.ListOptions)
+`
+
+const informersCommonTempl = `
+
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by kcp code-generator. DO NOT EDIT.
+
+package {{.Version}}
+
+import (
+	"context"
+	"time"
+
+	{{.Name}}api{{.Version}} "{{.APIPath}}"
+	{{.Name}}{{.Version}}listers "{{.ListersPkgPath}}"
+	"{{.ClientsetPkgPath}}"
+	"{{.InformersPkgPath}}/internalinterfaces"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/code-generator/third_party/informers"
+	"github.com/kcp-dev/logicalcluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Interface provides access to the informers of all the types in this group version.
+type Interface interface {
+	{{- range .Types }}
+	// {{.}}s returns a {{.}}Informer.
+	{{.}}s() {{.}}Informer
+	{{- end }}
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+`
+
+const informerTempl = `
+// {{.Name}}s returns a {{.Name}}Informer.
+func (v *version) {{.Name}}s() {{.Name}}Informer {
+	return &{{.NameLowerFirst}}Informer{factory: v.factory{{if .IsNamespaced}}, namespace: v.namespace{{end}}, tweakListOptions: v.tweakListOptions}
+}
+
+// {{.Name}}Informer provides access to a shared informer and lister for
+// {{.Name}}s across all logical clusters.
+type {{.Name}}Informer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() *{{.PkgName}}{{.Version}}listers.{{.Name}}ClusterLister
+}
+
+type {{.NameLowerFirst}}Informer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	{{- if .IsNamespaced}}
+	namespace        string
+	{{- end}}
+}
+
+// New{{.Name}}Informer constructs a new informer for {{.Name}} type, which lists and watches
+// across all logical clusters. Always prefer using an informer factory to get a shared informer
+// instead of getting an independent one. This reduces memory footprint and number of connections to the server.
+func New{{.Name}}Informer(client {{.ClientsetName}}.ClusterInterface{{if .IsNamespaced}}, namespace string{{end}}, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFiltered{{.Name}}Informer(client{{if .IsNamespaced}}, namespace{{end}}, resyncPeriod, indexers, nil)
+}
+
+// NewFiltered{{.Name}}Informer constructs a new informer for {{.Name}} type, which lists and watches
+// across all logical clusters. Always prefer using an informer factory to get a shared informer
+// instead of getting an independent one. This reduces memory footprint and number of connections to the server.
+func NewFiltered{{.Name}}Informer(client {{.ClientsetName}}.ClusterInterface{{if .IsNamespaced}}, namespace string{{end}}, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return informers.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.Cluster(logicalcluster.Wildcard).{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}().{{.Name}}s({{if .IsNamespaced}}namespace{{end}}).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.Cluster(logicalcluster.Wildcard).{{.PkgNameUpperFirst}}{{.VersionUpperFirst}}().{{.Name}}s({{if .IsNamespaced}}namespace{{end}}).Watch(context.TODO(), options)
+			},
+		},
+		&{{.PkgName}}api{{.Version}}.{{.Name}}{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *{{.NameLowerFirst}}Informer) defaultInformer(client {{.ClientsetName}}.ClusterInterface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFiltered{{.Name}}Informer(client{{if .IsNamespaced}}, f.namespace{{end}}, resyncPeriod, cache.Indexers{
+		kcpcache.ClusterIndexName:             kcpcache.ClusterIndexFunc,
+		kcpcache.ClusterAndNamespaceIndexName: kcpcache.ClusterAndNamespaceIndexFunc,
+	}, f.tweakListOptions)
+}
+
+// Informer returns the shared informer for {{.Name}}s.
+func (f *{{.NameLowerFirst}}Informer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&{{.PkgName}}api{{.Version}}.{{.Name}}{}, f.defaultInformer)
+}
+
+// Lister returns a cluster-aware lister backed by the shared informer for {{.Name}}s.
+func (f *{{.NameLowerFirst}}Informer) Lister() *{{.PkgName}}{{.Version}}listers.{{.Name}}ClusterLister {
+	return {{.PkgName}}{{.Version}}listers.New{{.Name}}ClusterLister(f.Informer().GetIndexer())
 }
 `
@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/code-generator/cmd/client-gen/types"
+)
+
+const (
+	// groupNameMarker declares the group of an API package in its doc.go.
+	groupNameMarker = "+groupName="
+	// genclientMarker enables the generation of a client for a type.
+	genclientMarker = "+genclient"
+)
+
+// DiscoverGroupVersions walks the input directory for API packages, which declare their
+// group with a +groupName marker in doc.go and have at least one +genclient type. They are
+// returned as <group>:<version>, as accepted by GetGroupVersions, the group and the version
+// being the directories of the package under the input directory.
+func DiscoverGroupVersions(inputDir string) ([]string, error) {
+	var gvs []string
+	err := filepath.WalkDir(inputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		// skip the directories ignored by the go tool.
+		name := d.Name()
+		if path != inputDir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+
+		ok, err := isAPIPackage(path)
+		if err != nil || !ok {
+			return err
+		}
+		rel, err := filepath.Rel(inputDir, path)
+		if err != nil {
+			return err
+		}
+		elems := strings.Split(filepath.ToSlash(rel), "/")
+		if len(elems) != 2 {
+			return fmt.Errorf("API package %s is not found at <input-dir>/<group>/<version>", path)
+		}
+		gvs = append(gvs, elems[0]+":"+elems[1])
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error discovering group versions: %w", err)
+	}
+	if len(gvs) == 0 {
+		return nil, fmt.Errorf("no package with a %s marker in doc.go and a %s type found in %s", groupNameMarker, genclientMarker, inputDir)
+	}
+	return gvs, nil
+}
+
+// ResolveGroupVersions returns the given group versions, or the ones discovered in the
+// input directory if discover is set. The packages of the inputs, in the
+// <group>/<version>=<path> format, then override the ones of the same group versions,
+// or are added to them.
+func ResolveGroupVersions(inputDir string, gvs []string, discover bool, inputs []string) ([]types.GroupVersions, error) {
+	if discover {
+		var err error
+		gvs, err = DiscoverGroupVersions(inputDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result, err := GetGroupVersions(inputDir, gvs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, input := range inputs {
+		gv, err := ParseInput(inputDir, input)
+		if err != nil {
+			return nil, err
+		}
+		overridden := false
+		for i := range result {
+			if result[i].Group == gv.Group && result[i].Versions[0].Version == gv.Versions[0].Version {
+				result[i] = gv
+				overridden = true
+			}
+		}
+		if !overridden {
+			result = append(result, gv)
+		}
+	}
+	return result, nil
+}
+
+// DocMarker returns the value of the +<name>= marker in the doc.go of the package in
+// the given directory, or "" if it has none.
+func DocMarker(dir, name string) (string, error) {
+	doc, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "doc.go"), nil, parser.ParseComments)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	prefix := "+" + name + "="
+	var value string
+	hasMarker(doc, func(marker string) bool {
+		if !strings.HasPrefix(marker, prefix) {
+			return false
+		}
+		value = strings.TrimPrefix(marker, prefix)
+		return true
+	})
+	return value, nil
+}
+
+// isAPIPackage returns true if the package in the given directory has a +groupName
+// marker in its doc.go, and at least one type marked with +genclient.
+func isAPIPackage(dir string) (bool, error) {
+	fset := token.NewFileSet()
+	doc, err := parser.ParseFile(fset, filepath.Join(dir, "doc.go"), nil, parser.ParseComments)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !hasMarker(doc, func(marker string) bool { return strings.HasPrefix(marker, groupNameMarker) }) {
+		return false, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return false, err
+		}
+		if hasMarker(file, func(marker string) bool { return marker == genclientMarker }) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasMarker returns true if one of the comment lines of the file is a marker
+// matching the given function.
+func hasMarker(file *ast.File, match func(marker string) bool) bool {
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if match(strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"k8s.io/code-generator/cmd/client-gen/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test group version discovery", func() {
+	f := newFixture("discover")
+
+	It("should discover the examples", func() {
+		gvs, err := DiscoverGroupVersions("../../examples/pkg/apis")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gvs).To(Equal([]string{"example:v1"}))
+	})
+
+	It("should only discover packages with a +groupName marker and +genclient types", func() {
+		f.writeFile("apps/v1/doc.go", "// +groupName=apps.example.dev\npackage v1\n")
+		f.writeFile("apps/v1/types.go", "package v1\n\n// +genclient\ntype Deployment struct{}\n")
+		f.writeFile("apps/v2/doc.go", "// +groupName=apps.example.dev\npackage v2\n")
+		f.writeFile("apps/v2/types.go", "package v2\n\n// +genclient:nonNamespaced\ntype Deployment struct{}\n")
+		f.writeFile("batch/v1/types.go", "package v1\n\n// +genclient\ntype Job struct{}\n")
+		f.writeFile("rbac/v1/doc.go", "// +groupName=rbac.example.dev\npackage v1\n")
+		f.writeFile("rbac/v1/types.go", "package v1\n\n// +genclient\n// +genclient:nonNamespaced\ntype Role struct{}\n")
+
+		gvs, err := DiscoverGroupVersions(f.dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gvs).To(Equal([]string{"apps:v1", "rbac:v1"}))
+	})
+
+	It("should error when no package is found", func() {
+		f.writeFile("apps/v1/types.go", "package v1\n\n// +genclient\ntype Deployment struct{}\n")
+		_, err := DiscoverGroupVersions(f.dir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no package with a +groupName= marker"))
+	})
+
+	It("should error on packages not found at <group>/<version>", func() {
+		f.writeFile("example.dev/apps/v1/doc.go", "// +groupName=apps.example.dev\npackage v1\n")
+		f.writeFile("example.dev/apps/v1/types.go", "package v1\n\n// +genclient\ntype Deployment struct{}\n")
+		_, err := DiscoverGroupVersions(f.dir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is not found at <input-dir>/<group>/<version>"))
+	})
+})
+
+var _ = Describe("Test group version resolution", func() {
+	It("should map group versions to packages relative to the input directory", func() {
+		gvs, err := ResolveGroupVersions("apis", []string{"apps:v1", "rbac:v1"}, false, []string{"apps/v1=./apps/v1alpha1", "batch/v1=/src/batch/v1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gvs).To(Equal([]types.GroupVersions{
+			{PackageName: "apps", Group: "apps", Versions: []types.PackageVersion{{Version: "v1", Package: "apis/apps/v1alpha1"}}},
+			{PackageName: "rbac", Group: "rbac", Versions: []types.PackageVersion{{Version: "v1", Package: "apis/rbac/v1"}}},
+			{PackageName: "batch", Group: "batch", Versions: []types.PackageVersion{{Version: "v1", Package: "/src/batch/v1"}}},
+		}))
+	})
+})
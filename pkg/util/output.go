@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+)
+
+// FileOutput is the output rule writing the generated files to disk.
+type FileOutput struct {
+	// Force overwrites the existing files which were not generated, instead
+	// of failing.
+	Force bool
+}
+
+var _ genall.OutputRule = FileOutput{}
+
+// Open creates the file at the given path, along with its parent directories.
+// An existing file is only overwritten if it has the GeneratedMarker, unless
+// forced.
+func (o FileOutput) Open(_ *loader.Package, path string) (io.WriteCloser, error) {
+	if err := checkOverwrite(path, o.Force); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// checkOverwrite returns an error if the file at the given path exists without
+// the GeneratedMarker, unless forced.
+func checkOverwrite(path string, force bool) error {
+	if force {
+		return nil
+	}
+	generated, err := IsGenerated(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !generated {
+		return fmt.Errorf("refusing to overwrite %s, which does not have the %q header of generated files: move it away or use --force", path, GeneratedMarker)
+	}
+	return nil
+}
+
+// VerifyOutput is the output rule which renders the generated files in memory
+// and compares them with the ones on disk, without modifying the tree. The diff
+// of every stale file is printed.
+type VerifyOutput struct {
+	// Out is where the diffs are printed.
+	Out io.Writer
+
+	stale []string
+}
+
+var _ genall.OutputRule = &VerifyOutput{}
+
+// Open returns a writer which compares what is written to the file at the given
+// path once closed.
+func (o *VerifyOutput) Open(_ *loader.Package, path string) (io.WriteCloser, error) {
+	return &verifiedFile{output: o, path: path}, nil
+}
+
+// Stale returns the paths of the files which are missing or differ from the
+// generated ones, sorted.
+func (o *VerifyOutput) Stale() []string {
+	stale := append([]string(nil), o.stale...)
+	sort.Strings(stale)
+	return stale
+}
+
+// Err returns an error listing the stale files, if any.
+func (o *VerifyOutput) Err() error {
+	stale := o.Stale()
+	if len(stale) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d generated files are out of date, regenerate them: %v", len(stale), stale)
+}
+
+// Removed reports a generated file which is no longer produced, with its diff to
+// /dev/null.
+func (o *VerifyOutput) Removed(path string) error {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		FromFile: "a/" + filepath.ToSlash(path),
+		ToFile:   "/dev/null",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(o.Out, diff); err != nil {
+		return err
+	}
+	o.stale = append(o.stale, path)
+	return nil
+}
+
+// verifiedFile buffers the content of a generated file until it is closed.
+type verifiedFile struct {
+	bytes.Buffer
+	output *VerifyOutput
+	path   string
+}
+
+// Close compares the generated content with the file on disk and prints their
+// unified diff when they differ.
+func (f *verifiedFile) Close() error {
+	current, err := os.ReadFile(f.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil && bytes.Equal(current, f.Bytes()) {
+		return nil
+	}
+
+	// like git, a missing file is diffed from /dev/null.
+	fromFile := "a/" + filepath.ToSlash(f.path)
+	if err != nil {
+		fromFile = "/dev/null"
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(f.String()),
+		FromFile: fromFile,
+		ToFile:   "b/" + filepath.ToSlash(f.path),
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f.output.Out, diff); err != nil {
+		return err
+	}
+	f.output.stale = append(f.output.stale, f.path)
+	return nil
+}
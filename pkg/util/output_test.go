@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test file output", func() {
+	f := newFixture("output")
+
+	It("should only overwrite generated files unless forced", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+		f.writeFile("listers/apps/v1/expansion.go", "package v1\n")
+		path := f.path("listers/apps/v1")
+
+		Expect(WriteContent(FileOutput{}, []byte(generated), "appsv1.go", path)).To(Succeed())
+		Expect(WriteContent(FileOutput{}, []byte(generated), "new.go", path)).To(Succeed())
+		err := WriteContent(FileOutput{}, []byte(generated), "expansion.go", path)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("refusing to overwrite"))
+		Expect(os.ReadFile(f.path("listers/apps/v1/expansion.go"))).To(Equal([]byte("package v1\n")))
+
+		Expect(WriteContent(FileOutput{Force: true}, []byte(generated), "expansion.go", path)).To(Succeed())
+		Expect(os.ReadFile(f.path("listers/apps/v1/expansion.go"))).To(Equal([]byte(generated)))
+	})
+})
+
+var _ = Describe("Test verify output", func() {
+	f := newFixture("verify")
+
+	var (
+		out    *bytes.Buffer
+		verify *VerifyOutput
+	)
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		verify = &VerifyOutput{Out: out}
+	})
+
+	It("should not report the files which are up to date", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+		Expect(WriteContent(verify, []byte(generated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+		Expect(verify.Stale()).To(BeEmpty())
+		Expect(verify.Err()).NotTo(HaveOccurred())
+	})
+
+	It("should print the diff of the files which differ", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated+"type A struct{}\n")
+		Expect(WriteContent(verify, []byte(generated+"type B struct{}\n"), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+
+		path := f.path("listers/apps/v1/appsv1.go")
+		Expect(out.String()).To(HavePrefix("--- a/" + path + "\n+++ b/" + path + "\n"))
+		Expect(out.String()).To(ContainSubstring("\n-type A struct{}\n+type B struct{}\n"))
+		Expect(os.ReadFile(path)).To(Equal([]byte(generated + "type A struct{}\n")))
+		Expect(verify.Stale()).To(Equal([]string{path}))
+		Expect(verify.Err()).To(MatchError(ContainSubstring("1 generated files are out of date")))
+	})
+
+	It("should diff the missing files from /dev/null", func() {
+		Expect(WriteContent(verify, []byte(generated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+
+		path := f.path("listers/apps/v1/appsv1.go")
+		Expect(out.String()).To(HavePrefix("--- /dev/null\n+++ b/" + path + "\n"))
+		Expect(path).NotTo(BeAnExistingFile())
+		Expect(verify.Stale()).To(Equal([]string{path}))
+	})
+
+	It("should diff the removed files to /dev/null", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+
+		path := f.path("listers/apps/v1/appsv1.go")
+		Expect(verify.Removed(path)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("--- a/" + path + "\n+++ /dev/null\n"))
+		Expect(path).To(BeAnExistingFile())
+		Expect(verify.Err()).To(HaveOccurred())
+	})
+})
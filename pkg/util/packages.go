@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// PackagePath returns the import path of the package in the given directory, which
+// does not need to exist yet, such as an output directory.
+//
+// The import path of an existing package is resolved by go/packages, and so by the
+// go command, which accounts for go.work workspaces, vendor directories, nested
+// modules and replace directives. Else it is the one of the parent directory joined
+// with the name of the directory, up to the root of the module or a vendor directory.
+func PackagePath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	pkgPath, err := packagePath(abs)
+	if err != nil {
+		return "", fmt.Errorf("error finding the go package of %s: %w", dir, err)
+	}
+	return pkgPath, nil
+}
+
+func packagePath(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if err == nil && !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if err == nil {
+		pkgPath, ok, err := loadPackagePath(dir)
+		if err != nil || ok {
+			return pkgPath, err
+		}
+
+		// the root of a module without Go files.
+		gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			modulePath := modfile.ModulePath(gomod)
+			if modulePath == "" {
+				return "", fmt.Errorf("no module path found in %s", filepath.Join(dir, "go.mod"))
+			}
+			return modulePath, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	parent := filepath.Dir(dir)
+	if parent == dir {
+		return "", fmt.Errorf("no go module found for %s", dir)
+	}
+	// the packages of a vendor directory are imported by their path inside it.
+	if filepath.Base(parent) == "vendor" {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(parent), "go.mod")); err == nil {
+			return filepath.Base(dir), nil
+		}
+	}
+	parentPath, err := packagePath(parent)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(parentPath, filepath.Base(dir)), nil
+}
+
+// loadPackagePath returns the import path of the package in the given directory, or
+// false if it has no Go files.
+func loadPackagePath(dir string) (string, bool, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles, Dir: dir}, ".")
+	if err != nil {
+		return "", false, err
+	}
+	if len(pkgs) != 1 {
+		return "", false, nil
+	}
+	pkg := pkgs[0]
+	if len(pkg.GoFiles) == 0 && len(pkg.OtherFiles) == 0 {
+		return "", false, nil
+	}
+	// errors in the package itself, such as in stale generated code, do not prevent
+	// importing it, unless the go command could not find its import path.
+	if pkg.PkgPath == "." || strings.HasPrefix(pkg.PkgPath, "_") {
+		if len(pkg.Errors) > 0 {
+			return "", false, pkg.Errors[0]
+		}
+		return "", false, fmt.Errorf("%s is not in a go module", dir)
+	}
+	return pkg.PkgPath, true, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test output package paths", func() {
+	f := newFixture("packages")
+
+	It("should resolve the packages of the examples", func() {
+		Expect(PackagePath("../../examples/pkg/listers")).To(Equal("github.com/kcp-dev/code-generator/examples/pkg/listers"))
+		Expect(PackagePath("../../examples/pkg/notgenerated/yet")).To(Equal("github.com/kcp-dev/code-generator/examples/pkg/notgenerated/yet"))
+	})
+
+	It("should resolve the packages of nested modules", func() {
+		f.writeFile("go.mod", "module example.com/outer\n\ngo 1.18\n")
+		f.writeFile("nested/go.mod", "module example.com/nested\n\ngo 1.18\n")
+		Expect(PackagePath(f.path("pkg"))).To(Equal("example.com/outer/pkg"))
+		Expect(PackagePath(f.path("nested/pkg/generated"))).To(Equal("example.com/nested/pkg/generated"))
+	})
+
+	It("should resolve the packages of vendor directories", func() {
+		f.writeFile("go.mod", "module example.com/outer\n\ngo 1.18\n")
+		f.writeFile("vendor/example.com/dep/pkg/doc.go", "package pkg\n")
+		Expect(PackagePath(f.path("vendor/example.com/dep/pkg"))).To(Equal("example.com/dep/pkg"))
+		// the packages which do not exist yet are resolved from their parents.
+		Expect(PackagePath(f.path("vendor/example.com/dep/pkg/generated"))).To(Equal("example.com/dep/pkg/generated"))
+		Expect(PackagePath(f.path("vendor/example.com/other"))).To(Equal("example.com/other"))
+	})
+
+	Context("in a go.work workspace", func() {
+		var goflags string
+		BeforeEach(func() {
+			// the go command rejects -mod=mod in workspace mode.
+			goflags = os.Getenv("GOFLAGS")
+			Expect(os.Setenv("GOFLAGS", "")).To(Succeed())
+
+			f.writeFile("go.work", "go 1.18\n\nuse (\n\t./api\n\t./client\n)\n")
+			f.writeFile("api/go.mod", "module example.com/api\n\ngo 1.18\n")
+			f.writeFile("api/v1/types.go", "package v1\n")
+			f.writeFile("client/go.mod", "module example.com/client\n\ngo 1.18\n")
+		})
+		AfterEach(func() {
+			Expect(os.Setenv("GOFLAGS", goflags)).To(Succeed())
+		})
+
+		It("should resolve the packages of the modules of the workspace", func() {
+			Expect(PackagePath(f.path("api/v1"))).To(Equal("example.com/api/v1"))
+			Expect(PackagePath(f.path("client"))).To(Equal("example.com/client"))
+			Expect(PackagePath(f.path("client/generated/listers"))).To(Equal("example.com/client/generated/listers"))
+		})
+	})
+
+	It("should error outside of a module", func() {
+		_, err := PackagePath(f.path("pkg"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"runtime"
+	"sync"
+
+	"k8s.io/code-generator/cmd/client-gen/types"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+)
+
+// GeneratedFile is a file rendered by a generator, which is written once every
+// group version is rendered.
+type GeneratedFile struct {
+	// Dir is the directory of the file.
+	Dir string
+	// Filename is the name of the file.
+	Filename string
+	// Content is the formatted content of the file.
+	Content []byte
+}
+
+// RenderedGroupVersion is the output of a generator for a group version.
+type RenderedGroupVersion struct {
+	// Roots are the packages loaded for the group version, whose errors are
+	// printed once the generator ran.
+	Roots []*loader.Package
+	// Files are the files generated for the group version.
+	Files []GeneratedFile
+}
+
+// RenderGroupVersions renders the group versions concurrently, with at most the given
+// parallelism, or GOMAXPROCS if it is not positive. Their files are then written
+// through the output rule of the context, in the order of the group versions, so that
+// the output does not depend on the scheduling. The roots of all the group versions
+// are added to the context.
+//
+// If rendering fails, nothing is written and the error of the first group version
+// which failed is returned.
+func RenderGroupVersions(ctx *genall.GenerationContext, gvs []types.GroupVersions, parallelism int, render func(gv types.GroupVersions) (*RenderedGroupVersion, error)) error {
+	rendered := make([]*RenderedGroupVersion, len(gvs))
+	if err := ForEach(len(gvs), parallelism, func(i int) (err error) {
+		rendered[i], err = render(gvs[i])
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, r := range rendered {
+		if r == nil {
+			continue
+		}
+		ctx.Roots = append(ctx.Roots, r.Roots...)
+		for _, f := range r.Files {
+			if err := WriteContent(ctx.OutputRule, f.Content, f.Filename, f.Dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ForEach calls f for every index in [0, n), with at most the given parallelism, or
+// GOMAXPROCS if it is not positive. No more calls are started once one fails, and the
+// error of the lowest index is returned.
+func ForEach(n, parallelism int, f func(i int) error) error {
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+		errs   = make([]error, n)
+		sem    = make(chan struct{}, parallelism)
+	)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := f(i); err != nil {
+				mu.Lock()
+				errs[i], failed = err, true
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test parallel rendering", func() {
+	It("should bound the calls running at once", func() {
+		var running, max int32
+		calls := make([]bool, 20)
+		Expect(ForEach(len(calls), 3, func(i int) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			calls[i] = true
+			return nil
+		})).To(Succeed())
+		Expect(max).To(BeNumerically("<=", 3))
+		Expect(calls).NotTo(ContainElement(false))
+	})
+
+	It("should return the error of the lowest index", func() {
+		err := ForEach(10, 4, func(i int) error {
+			if i >= 2 {
+				// the later calls fail first.
+				time.Sleep(time.Duration(10-i) * time.Millisecond)
+				return fmt.Errorf("error %d", i)
+			}
+			return nil
+		})
+		Expect(err).To(MatchError("error 2"))
+	})
+})
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+)
+
+// GeneratedMarker is the line marking the files generated by the generators.
+const GeneratedMarker = "// Code generated by kcp code-generator. DO NOT EDIT."
+
+// TrackedOutput is the output rule recording the files written through the
+// output rule it wraps, so that the previously generated files which are no
+// longer produced are found.
+type TrackedOutput struct {
+	genall.OutputRule
+
+	produced map[string]bool
+}
+
+var _ genall.OutputRule = &TrackedOutput{}
+
+// Open records the path and opens it with the wrapped output rule.
+func (o *TrackedOutput) Open(pkg *loader.Package, path string) (io.WriteCloser, error) {
+	if o.produced == nil {
+		o.produced = map[string]bool{}
+	}
+	o.produced[filepath.Clean(path)] = true
+	return o.OutputRule.Open(pkg, path)
+}
+
+// Stale returns the generated files found in the given directories which were not
+// produced, sorted.
+func (o *TrackedOutput) Stale(dirs ...string) ([]string, error) {
+	var stale []string
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".go") || o.produced[filepath.Clean(path)] {
+				return nil
+			}
+			generated, err := IsGenerated(path)
+			if err != nil {
+				return err
+			}
+			if generated {
+				stale = append(stale, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(stale)
+	// the directories of the generators may be nested in one another.
+	result := stale[:0]
+	for i, path := range stale {
+		if i == 0 || path != stale[i-1] {
+			result = append(result, path)
+		}
+	}
+	return result, nil
+}
+
+// IsGenerated returns true if the file at the given path has the GeneratedMarker
+// before its package clause.
+func IsGenerated(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == GeneratedMarker {
+			return true, nil
+		}
+		if strings.HasPrefix(line, "package ") {
+			return false, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// generated is the content of a generated file.
+const generated = "/*\nheader\n*/\n\n" + GeneratedMarker + "\n\npackage v1\n"
+
+var _ = Describe("Test pruning", func() {
+	f := newFixture("prune")
+
+	It("should only prune the generated files which are not produced", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+		f.writeFile("listers/apps/v2/appsv2.go", generated)
+		f.writeFile("listers/apps/v2/expansion.go", "package v2\n")
+		f.writeFile("listers/rbac/v1/rbacv1.go", generated)
+		f.writeFile("listers/rbac/v1/doc.go", "package v1\n\n"+GeneratedMarker+"\n")
+
+		tracked := &TrackedOutput{OutputRule: FileOutput{}}
+		Expect(WriteContent(tracked, []byte(generated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+
+		root := f.path("listers")
+		stale, err := tracked.Stale(root, f.path("informers"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(Equal([]string{
+			f.path("listers/apps/v2/appsv2.go"),
+			f.path("listers/rbac/v1/rbacv1.go"),
+		}))
+
+		staged := &StagedOutput{}
+		staged.Remove(stale, root)
+		Expect(staged.Commit()).To(Succeed())
+		Expect(f.path("listers/apps/v1/appsv1.go")).To(BeAnExistingFile())
+		Expect(f.path("listers/apps/v2/expansion.go")).To(BeAnExistingFile())
+		Expect(f.path("listers/rbac/v1/doc.go")).To(BeAnExistingFile())
+		Expect(f.path("listers/apps/v2/appsv2.go")).NotTo(BeAnExistingFile())
+	})
+
+	It("should remove the directories left empty", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+		root := f.path("listers")
+		stale, err := (&TrackedOutput{OutputRule: FileOutput{}}).Stale(root)
+		Expect(err).NotTo(HaveOccurred())
+		staged := &StagedOutput{}
+		staged.Remove(stale, root)
+		Expect(staged.Commit()).To(Succeed())
+		Expect(f.path("listers/apps")).NotTo(BeADirectory())
+		Expect(root).To(BeADirectory())
+	})
+})
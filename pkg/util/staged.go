@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+)
+
+// StagedOutput is the output rule writing the generated files to a staging
+// directory, until they are all moved to their path with Commit, along with the
+// removal of the files given to Remove. Nothing is written to the output tree if
+// the generation fails halfway, and a failing Commit restores the previous tree.
+//
+// The staging directory is created in the temporary directory, out of the output
+// tree, so that the generators never see it and a killed generation does not leave
+// it behind. The files are moved by renames, or copied when the temporary directory
+// is on another file system.
+type StagedOutput struct {
+	// Force overwrites the existing files which were not generated, instead
+	// of failing.
+	Force bool
+
+	staging string
+	// staged maps the path of every file to its staged file, in the order they
+	// were opened.
+	staged map[string]string
+	paths  []string
+	// removed are the files to remove, and roots the directories up to which
+	// the directories they leave empty are removed.
+	removed []string
+	roots   map[string]bool
+}
+
+var _ genall.OutputRule = &StagedOutput{}
+
+// Open creates the staged file of the given path. Like for FileOutput, an existing
+// file is only overwritten if it has the GeneratedMarker, unless forced.
+func (o *StagedOutput) Open(_ *loader.Package, path string) (io.WriteCloser, error) {
+	if err := checkOverwrite(path, o.Force); err != nil {
+		return nil, err
+	}
+
+	path = filepath.Clean(path)
+	staged, ok := o.staged[path]
+	if !ok {
+		staging, err := o.stagingDir()
+		if err != nil {
+			return nil, err
+		}
+		staged = filepath.Join(staging, fmt.Sprintf("%d%s", len(o.paths), filepath.Ext(path)))
+		o.staged[path] = staged
+		o.paths = append(o.paths, path)
+	}
+	return os.Create(staged)
+}
+
+// Remove stages the removal of the given files, then of their parent directories
+// left empty, up to the given root directories.
+func (o *StagedOutput) Remove(paths []string, roots ...string) {
+	if o.roots == nil {
+		o.roots = map[string]bool{}
+	}
+	for _, root := range roots {
+		o.roots[filepath.Clean(root)] = true
+	}
+	for _, path := range paths {
+		o.removed = append(o.removed, filepath.Clean(path))
+	}
+}
+
+// stagingDir returns the staging directory, which is created the first time.
+func (o *StagedOutput) stagingDir() (string, error) {
+	if o.staging == "" {
+		staging, err := os.MkdirTemp("", "code-generator-")
+		if err != nil {
+			return "", fmt.Errorf("error creating the staging directory: %w", err)
+		}
+		o.staging, o.staged = staging, map[string]string{}
+	}
+	return o.staging, nil
+}
+
+// Commit moves the staged files to their paths, and removes the files given to
+// Remove. If any of them cannot be moved or removed, the changes already made are
+// reverted and the error is returned.
+func (o *StagedOutput) Commit() error {
+	defer o.Discard()
+	if len(o.paths) == 0 && len(o.removed) == 0 {
+		return nil
+	}
+	staging, err := o.stagingDir()
+	if err != nil {
+		return err
+	}
+
+	// the changes to the output tree, undone in reverse order on failure.
+	var undo []func() error
+	rollback := func(err error) error {
+		var undoErr error
+		for i := len(undo) - 1; i >= 0; i-- {
+			if e := undo[i](); e != nil && undoErr == nil {
+				undoErr = e
+			}
+		}
+		if undoErr != nil {
+			return fmt.Errorf("%w, and the output could not be restored: %v", err, undoErr)
+		}
+		return err
+	}
+	// backup moves the existing file to the staging directory, to be restored
+	// on failure.
+	backup := func(path, name string) error {
+		backup := filepath.Join(staging, name)
+		if err := move(path, backup); err != nil {
+			return err
+		}
+		undo = append(undo, func() error { return move(backup, path) })
+		return nil
+	}
+
+	for i, path := range o.paths {
+		dirs, err := createDirs(filepath.Dir(path))
+		for _, dir := range dirs {
+			dir := dir
+			undo = append(undo, func() error { return os.Remove(dir) })
+		}
+		if err != nil {
+			return rollback(err)
+		}
+
+		if err := backup(path, fmt.Sprintf("%d.orig", i)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return rollback(err)
+		}
+		if err := move(o.staged[path], path); err != nil {
+			return rollback(fmt.Errorf("error writing %s: %w", path, err))
+		}
+		path := path
+		undo = append(undo, func() error { return os.Remove(path) })
+	}
+
+	for i, path := range o.removed {
+		if err := backup(path, fmt.Sprintf("%d.removed", i)); err != nil {
+			return rollback(fmt.Errorf("error removing %s: %w", path, err))
+		}
+		for dir := filepath.Dir(path); !o.roots[dir] && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return rollback(err)
+			}
+			if len(entries) > 0 {
+				break
+			}
+			if err := os.Remove(dir); err != nil {
+				return rollback(err)
+			}
+			dir := dir
+			undo = append(undo, func() error { return os.Mkdir(dir, 0755) })
+		}
+	}
+	return nil
+}
+
+// Discard removes the staging directory and forgets the staged removals, leaving
+// the output tree untouched.
+func (o *StagedOutput) Discard() error {
+	o.removed, o.roots = nil, nil
+	if o.staging == "" {
+		return nil
+	}
+	err := os.RemoveAll(o.staging)
+	o.staging, o.staged, o.paths = "", nil, nil
+	return err
+}
+
+// move renames the file, or copies then removes it when it is on another file
+// system than its destination.
+func move(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// createDirs creates the directory and its missing parents, and returns the ones it
+// created, from the outermost.
+func createDirs(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		missing = append([]string{d}, missing...)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	var created []string
+	for _, d := range missing {
+		if err := os.Mkdir(d, 0755); err != nil {
+			return created, err
+		}
+		created = append(created, d)
+	}
+	return created, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test staged output", func() {
+	f := newFixture("staged")
+	updated := generated + "\ntype A struct{}\n"
+
+	// tree returns the paths found in the directory of the fixture.
+	tree := func() []string {
+		var paths []string
+		Expect(filepath.WalkDir(f.dir, func(path string, _ fs.DirEntry, err error) error {
+			if err != nil || path == f.dir {
+				return err
+			}
+			rel, err := filepath.Rel(f.dir, path)
+			paths = append(paths, filepath.ToSlash(rel))
+			return err
+		})).To(Succeed())
+		return paths
+	}
+
+	It("should only write the staged files once committed", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+
+		staged := &StagedOutput{}
+		Expect(WriteContent(staged, []byte(updated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+		Expect(WriteContent(staged, []byte(updated), "rbacv1.go", f.path("listers/rbac/v1"))).To(Succeed())
+		Expect(os.ReadFile(f.path("listers/apps/v1/appsv1.go"))).To(Equal([]byte(generated)))
+		Expect(f.path("listers/rbac")).NotTo(BeADirectory())
+		// the staging directory is out of the output tree.
+		Expect(tree()).To(Equal([]string{"listers", "listers/apps", "listers/apps/v1", "listers/apps/v1/appsv1.go"}))
+		staging := staged.staging
+		Expect(staging).To(BeADirectory())
+
+		Expect(staged.Commit()).To(Succeed())
+		Expect(os.ReadFile(f.path("listers/apps/v1/appsv1.go"))).To(Equal([]byte(updated)))
+		Expect(os.ReadFile(f.path("listers/rbac/v1/rbacv1.go"))).To(Equal([]byte(updated)))
+		Expect(staging).NotTo(BeADirectory())
+	})
+
+	It("should only remove the files once committed", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+		f.writeFile("listers/rbac/v1/rbacv1.go", generated)
+
+		staged := &StagedOutput{}
+		Expect(WriteContent(staged, []byte(updated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+		staged.Remove([]string{f.path("listers/rbac/v1/rbacv1.go")}, f.path("listers"))
+		Expect(f.path("listers/rbac/v1/rbacv1.go")).To(BeAnExistingFile())
+
+		Expect(staged.Commit()).To(Succeed())
+		Expect(os.ReadFile(f.path("listers/apps/v1/appsv1.go"))).To(Equal([]byte(updated)))
+		Expect(tree()).To(Equal([]string{"listers", "listers/apps", "listers/apps/v1", "listers/apps/v1/appsv1.go"}))
+	})
+
+	It("should restore the previous files when the commit fails", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+
+		staged := &StagedOutput{}
+		Expect(WriteContent(staged, []byte(updated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+		Expect(WriteContent(staged, []byte(updated), "new.go", f.path("listers/rbac/v1"))).To(Succeed())
+		Expect(WriteContent(staged, []byte(updated), "blocked.go", f.path("listers/blocker"))).To(Succeed())
+		// the file cannot be moved to a directory which is a file.
+		f.writeFile("listers/blocker", "")
+
+		staging := staged.staging
+		Expect(staged.Commit()).NotTo(Succeed())
+		Expect(os.ReadFile(f.path("listers/apps/v1/appsv1.go"))).To(Equal([]byte(generated)))
+		Expect(f.path("listers/rbac")).NotTo(BeADirectory())
+		Expect(staging).NotTo(BeADirectory())
+	})
+
+	It("should restore the removed files when the commit fails", func() {
+		f.writeFile("listers/apps/v1/appsv1.go", generated)
+		f.writeFile("listers/rbac/v1/rbacv1.go", generated)
+
+		staged := &StagedOutput{}
+		Expect(WriteContent(staged, []byte(updated), "appsv1.go", f.path("listers/apps/v1"))).To(Succeed())
+		// the second file does not exist, so it cannot be removed.
+		staged.Remove([]string{f.path("listers/rbac/v1/rbacv1.go"), f.path("listers/batch/v1/batchv1.go")}, f.path("listers"))
+
+		Expect(staged.Commit()).NotTo(Succeed())
+		Expect(os.ReadFile(f.path("listers/apps/v1/appsv1.go"))).To(Equal([]byte(generated)))
+		Expect(os.ReadFile(f.path("listers/rbac/v1/rbacv1.go"))).To(Equal([]byte(generated)))
+	})
+})
@@ -18,84 +18,92 @@ package util
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"golang.org/x/mod/modfile"
+	"k8s.io/code-generator/cmd/client-gen/args"
+	"k8s.io/code-generator/cmd/client-gen/types"
+	"sigs.k8s.io/controller-tools/pkg/genall"
 )
 
-// CurrentPackage returns the go package of the current directory, or "" if it cannot
-// be derived from the GOPATH.
-// This logic is taken from k8.io/code-generator, but has a change of letting user pass the
-// directory whose pacakge is to be found.
-func CurrentPackage(dir string) (string, bool) {
-	goModPath, err := getGoModPath(dir)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+// GetHeaderText reads the text passed through the file present in the
+// path.
+func GetHeaderText(path string) (string, error) {
+	var headertext string
+	if path != "" {
+		headerBytes, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		headertext = string(headerBytes)
 	}
+	return headertext, nil
+}
 
-	// hasGoMod returns true if go.mod was found in the parent dir which was
-	// given as input.
-	var hasGoMod bool
-	if goModPath == dir {
-		hasGoMod = true
-	} else {
-		hasGoMod = false
-	}
+// GetGroupVersions parses the Group Versions provided in the input through flags
+// and creates a list of []types.GroupVersions.
+func GetGroupVersions(inputDir string, gvs []string) ([]types.GroupVersions, error) {
+	var result []types.GroupVersions
+	for _, gv := range gvs {
+		// arr[0] -> group, arr[1] -> versions
+		arr := strings.Split(gv, ":")
+		if len(arr) != 2 {
+			return nil, fmt.Errorf("input to --group-version must be in <group>:<versions> format, ex: rbac:v1. Got %q", gv)
+		}
 
-	gomod, err := ioutil.ReadFile(filepath.Join(goModPath, "go.mod"))
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		versions := strings.Split(arr[1], ",")
+		for _, v := range versions {
+			// input path is converted to <inputDir>/<group>/<version>.
+			// example for input directory of "k8s.io/client-go/kubernetes/pkg/apis/", it would
+			// be converted to "k8s.io/client-go/kubernetes/pkg/apis/rbac/v1".
+			input := filepath.Join(inputDir, arr[0], v)
+			groups := []types.GroupVersions{}
+			builder := args.NewGroupVersionsBuilder(&groups)
+			_ = args.NewGVPackagesValue(builder, []string{input})
+
+			result = append(result, groups...)
+		}
 	}
-	return modfile.ModulePath(gomod), hasGoMod
+	return result, nil
 }
 
-// getGoModPath recursively traverses up the directory path
-// to find the location of go.mod file.
-func getGoModPath(dir string) (string, error) {
-	if _, err := os.Stat(dir); err != nil {
-		return "", fmt.Errorf("error trying to find go.mod from directory %s: %w", dir, err)
-	}
-	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
-		return dir, nil
+// ParseInput parses an input in the <group>/<version>=<path> format, which maps the
+// group version to the package in the given directory, relative to the input
+// directory unless absolute. Like internal.NewGroup, the packages of the group
+// version are named after the first segment of the group, lower-cased, so that
+// apps.example.com/v1 is found in the apps packages.
+func ParseInput(inputDir, input string) (types.GroupVersions, error) {
+	gv, path, ok := strings.Cut(input, "=")
+	group, version, hasVersion := strings.Cut(gv, "/")
+	if !ok || !hasVersion || group == "" || version == "" || path == "" {
+		return types.GroupVersions{}, fmt.Errorf("input to --input must be in <group>/<version>=<path> format, ex: rbac/v1=./apis/rbac/v1. Got %q", input)
 	}
-
-	if filepath.Dir(dir) == dir {
-		// Hit the root
-		return "", fmt.Errorf("could not find go.mod")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(inputDir, path)
 	}
-
-	return getGoModPath(filepath.Dir(dir))
+	return types.GroupVersions{
+		PackageName: strings.ToLower(strings.Split(group, ".")[0]),
+		Group:       types.Group(group),
+		Versions:    []types.PackageVersion{{Version: types.Version(version), Package: path}},
+	}, nil
 }
 
-// CleanInputDir returns a clean directory path. If
-// the input is ".", it returns an empty string.
-func CleanInputDir(dir string) (cleanPath string) {
-	if dir == "." {
-		return cleanPath
+// WriteContent writes the contents to the file with the given name under the
+// given path, through the output rule. The file is written to disk when no rule
+// is given.
+func WriteContent(rule genall.OutputRule, outBytes []byte, filename string, path string) error {
+	if rule == nil {
+		rule = FileOutput{}
 	}
-	return filepath.Clean(dir)
-}
 
-// GetCleanRealtivePath checks if the outputPath already consists of
-// the go's base path.If so, it returns the output path. In case it doesn't
-// then it combines the base path with the output path.
-// For example:
-// basePath := github.com/kcp-dev/kubernetes
-// outputPath := pkg/output
-// It would return github.com/kcp-dev/kubernetes/pkg/output
-// The other case in which:
-// basePath := github.com/kcp-dev/kubernetes
-// outputPath := github.com/kcp-dev/kubernetes/pkg/output
-// It would return github.com/kcp-dev/kubernetes/pkg/output
-func GetCleanRealtivePath(basePath, outputPath string) string {
-	if strings.HasPrefix(outputPath, basePath) {
-		return outputPath
+	w, err := rule.Open(nil, filepath.Join(path, filename))
+	if err != nil {
+		return err
 	}
-
-	return filepath.Join(basePath, filepath.Clean(outputPath))
+	if _, err := w.Write(outBytes); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
 }
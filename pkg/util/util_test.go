@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/code-generator/cmd/client-gen/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fixture is a temporary directory to write the files of a spec to.
+type fixture struct {
+	dir string
+}
+
+// newFixture returns a fixture whose directory is created before each spec of the
+// container it is called in, and removed after it.
+func newFixture(pattern string) *fixture {
+	f := &fixture{}
+	BeforeEach(func() {
+		var err error
+		f.dir, err = os.MkdirTemp("", pattern)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(f.dir)).To(Succeed())
+	})
+	return f
+}
+
+// path returns the path of the slash-separated path relative to the directory.
+func (f *fixture) path(path string) string {
+	return filepath.Join(f.dir, filepath.FromSlash(path))
+}
+
+// writeFile writes the file at the slash-separated path relative to the directory,
+// creating its parents.
+func (f *fixture) writeFile(path, content string) {
+	Expect(os.MkdirAll(filepath.Dir(f.path(path)), 0755)).To(Succeed())
+	Expect(os.WriteFile(f.path(path), []byte(content), 0644)).To(Succeed())
+}
+
+var _ = Describe("Test input packages", func() {
+	It("should resolve the path of the package against the input directory", func() {
+		gv, err := ParseInput("apis", "apps/v1=./apps/v1alpha1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gv).To(Equal(types.GroupVersions{
+			PackageName: "apps",
+			Group:       types.Group("apps"),
+			Versions:    []types.PackageVersion{{Version: "v1", Package: filepath.Join("apis", "apps", "v1alpha1")}},
+		}))
+	})
+
+	It("should name the packages of a dotted group after its first segment", func() {
+		gv, err := ParseInput("apis", "Apps.example.com/v1=/apps/v1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gv).To(Equal(types.GroupVersions{
+			PackageName: "apps",
+			Group:       types.Group("Apps.example.com"),
+			Versions:    []types.PackageVersion{{Version: "v1", Package: "/apps/v1"}},
+		}))
+	})
+
+	It("should error on inputs not in the <group>/<version>=<path> format", func() {
+		for _, input := range []string{"apps/v1", "apps=./apps", "/v1=./apps", "apps/v1="} {
+			_, err := ParseInput("apis", input)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be in <group>/<version>=<path> format"))
+		}
+	})
+})
+
+func TestUtil(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Test util suite")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+const (
+	// SkipVerbsMarkerName is the name of the marker listing the verbs which are not
+	// generated for a type, ex: +genclient:skipVerbs=get,update
+	SkipVerbsMarkerName = "genclient:skipVerbs"
+	// OnlyVerbsMarkerName is the name of the marker listing the only verbs which are
+	// generated for a type, ex: +genclient:onlyVerbs=create,get
+	OnlyVerbsMarkerName = "genclient:onlyVerbs"
+	// ReadonlyMarkerName is the name of the marker restricting the verbs generated for
+	// a type to the ReadonlyVerbs, ex: +genclient:readonly
+	ReadonlyMarkerName = "genclient:readonly"
+)
+
+// SupportedVerbs are the verbs client-gen generates for a type, and which can be
+// given to the skipVerbs and onlyVerbs markers.
+var SupportedVerbs = []string{
+	"create",
+	"update",
+	"updateStatus",
+	"delete",
+	"deleteCollection",
+	"get",
+	"list",
+	"watch",
+	"patch",
+	"apply",
+	"applyStatus",
+}
+
+// ReadonlyVerbs are the verbs client-gen generates for a read-only type.
+var ReadonlyVerbs = []string{
+	"get",
+	"list",
+	"watch",
+}
+
+// ClientVerbs returns the verbs client-gen generates for a type, keyed by verb. As with
+// client-gen, the onlyVerbs marker restricts the verbs to the ones listed, the readonly
+// marker adds the ReadonlyVerbs to those, and the skipVerbs marker removes the ones listed.
+// The skipVerbs and onlyVerbs markers are expected to be registered with
+// markers.RawArguments as their output, since their values are comma separated.
+//
+// The status verbs are returned as well, it is up to the caller to drop them for types
+// without a status subresource.
+func ClientVerbs(info *markers.TypeInfo) (map[string]bool, error) {
+	onlyVerbs, err := markerVerbs(info, OnlyVerbsMarkerName)
+	if err != nil {
+		return nil, err
+	}
+	skipVerbs, err := markerVerbs(info, SkipVerbsMarkerName)
+	if err != nil {
+		return nil, err
+	}
+	if len(onlyVerbs) > 0 && len(skipVerbs) > 0 {
+		return nil, fmt.Errorf("type %s: only one of %s and %s can be set", info.Name, OnlyVerbsMarkerName, SkipVerbsMarkerName)
+	}
+	if info.Markers.Get(ReadonlyMarkerName) != nil {
+		if len(skipVerbs) > 0 {
+			return nil, fmt.Errorf("type %s: only one of %s and %s can be set", info.Name, ReadonlyMarkerName, SkipVerbsMarkerName)
+		}
+		onlyVerbs = append(onlyVerbs, ReadonlyVerbs...)
+	}
+
+	verbs := make(map[string]bool, len(SupportedVerbs))
+	for _, verb := range SupportedVerbs {
+		verbs[verb] = len(onlyVerbs) == 0
+	}
+	for _, verb := range onlyVerbs {
+		verbs[verb] = true
+	}
+	for _, verb := range skipVerbs {
+		verbs[verb] = false
+	}
+	return verbs, nil
+}
+
+// markerVerbs returns the comma separated list of verbs given to a
+// skipVerbs or onlyVerbs marker. It errors on verbs client-gen doesn't know.
+func markerVerbs(info *markers.TypeInfo, name string) ([]string, error) {
+	value, ok := info.Markers.Get(name).(markers.RawArguments)
+	if !ok {
+		return nil, nil
+	}
+
+	var verbs []string
+	for _, verb := range strings.Split(string(value), ",") {
+		verb = strings.TrimSpace(verb)
+		if verb == "" {
+			continue
+		}
+		if !isSupportedVerb(verb) {
+			return nil, fmt.Errorf("type %s: unknown verb %q in %s, supported verbs are %s", info.Name, verb, name, strings.Join(SupportedVerbs, ","))
+		}
+		verbs = append(verbs, verb)
+	}
+	return verbs, nil
+}
+
+func isSupportedVerb(verb string) bool {
+	for _, v := range SupportedVerbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sigs.k8s.io/controller-tools/pkg/markers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test client verbs", func() {
+	var info *markers.TypeInfo
+	BeforeEach(func() {
+		info = &markers.TypeInfo{
+			Name:    "TestType",
+			Markers: markers.MarkerValues{},
+		}
+	})
+
+	It("should have every verb by default", func() {
+		verbs, err := ClientVerbs(info)
+		Expect(err).NotTo(HaveOccurred())
+		for _, verb := range []string{"create", "update", "updateStatus", "delete", "deleteCollection", "get", "list", "watch", "patch", "apply", "applyStatus"} {
+			Expect(verbs).To(HaveKeyWithValue(verb, true))
+		}
+	})
+
+	It("should skip the verbs listed in skipVerbs", func() {
+		info.Markers[SkipVerbsMarkerName] = []interface{}{markers.RawArguments("patch, watch")}
+		verbs, err := ClientVerbs(info)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verbs).To(HaveKeyWithValue("patch", false))
+		Expect(verbs).To(HaveKeyWithValue("watch", false))
+		Expect(verbs).To(HaveKeyWithValue("get", true))
+	})
+
+	It("should only keep the verbs listed in onlyVerbs", func() {
+		info.Markers[OnlyVerbsMarkerName] = []interface{}{markers.RawArguments("get,list")}
+		verbs, err := ClientVerbs(info)
+		Expect(err).NotTo(HaveOccurred())
+		for verb, ok := range verbs {
+			Expect(ok).To(Equal(verb == "get" || verb == "list"), verb)
+		}
+	})
+
+	It("should only keep the read-only verbs for readonly types", func() {
+		info.Markers[ReadonlyMarkerName] = []interface{}{struct{}{}}
+		verbs, err := ClientVerbs(info)
+		Expect(err).NotTo(HaveOccurred())
+		for verb, ok := range verbs {
+			Expect(ok).To(Equal(verb == "get" || verb == "list" || verb == "watch"), verb)
+		}
+	})
+
+	It("should error when both readonly and skipVerbs are set", func() {
+		info.Markers[ReadonlyMarkerName] = []interface{}{struct{}{}}
+		info.Markers[SkipVerbsMarkerName] = []interface{}{markers.RawArguments("watch")}
+		_, err := ClientVerbs(info)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should error on unknown verbs", func() {
+		info.Markers[SkipVerbsMarkerName] = []interface{}{markers.RawArguments("get,frobnicate")}
+		_, err := ClientVerbs(info)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`unknown verb "frobnicate"`))
+	})
+
+	It("should error when both skipVerbs and onlyVerbs are set", func() {
+		info.Markers[SkipVerbsMarkerName] = []interface{}{markers.RawArguments("get")}
+		info.Markers[OnlyVerbsMarkerName] = []interface{}{markers.RawArguments("list")}
+		_, err := ClientVerbs(info)
+		Expect(err).To(HaveOccurred())
+	})
+})